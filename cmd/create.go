@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/techsek/derivatex/constants"
 	"github.com/techsek/derivatex/internal"
 
 	"github.com/fatih/color"
@@ -14,7 +16,14 @@ import (
 )
 
 type createParams struct {
-	defaultUser string
+	defaultUser        string
+	randomSalt         bool
+	recover            bool
+	nonInteractive     bool
+	masterPasswordFrom string
+	birthdateFrom      string
+	passphraseFrom     string
+	force              bool
 }
 
 var createP createParams
@@ -22,20 +31,115 @@ var createP createParams
 func init() {
 	rootCmd.AddCommand(createCmd)
 	createCmd.Flags().StringVar(&createP.defaultUser, "user", "", "Your default user to be used when generating passwords without specifying a particular user")
+	createCmd.Flags().BoolVar(&createP.randomSalt, "randomsalt", false, "Use a random salt instead of your birthdate, given to you as a recovery code to write down")
+	createCmd.Flags().BoolVar(&createP.recover, "recover", false, "Recreate a seed created with --randomsalt from your master password and recovery code")
+	createCmd.Flags().BoolVar(&createP.nonInteractive, "non-interactive", false, "Create the seed without any prompt, reading the secrets from the sources given and printing the result as JSON")
+	createCmd.Flags().StringVar(&createP.masterPasswordFrom, "password-from", "", "Source of the master password with --non-interactive: env:NAME, file:PATH or fd:N")
+	createCmd.Flags().StringVar(&createP.birthdateFrom, "birthdate-from", "", "Source of the birthdate in the format dd/mm/yyyy with --non-interactive: env:NAME, file:PATH or fd:N")
+	createCmd.Flags().StringVar(&createP.passphraseFrom, "passphrase-from", "", "Source of the passphrase to encrypt the seed with --non-interactive: env:NAME, file:PATH or fd:N")
+	createCmd.Flags().BoolVar(&createP.force, "force", false, "Overwrite an existing seed file with --non-interactive")
+}
+
+// createResult is printed as JSON by create --non-interactive
+type createResult struct {
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	Profile     string `json:"profile,omitempty"`
+	DefaultUser string `json:"default_user,omitempty"`
+	Protection  string `json:"protection,omitempty"`
+}
+
+// printCreateResult prints the result as JSON and returns the exit code of the command
+func printCreateResult(result createResult) (exitCode int) {
+	output, _ := json.Marshal(result)
+	fmt.Println(string(output))
+	if !result.Success {
+		return 1
+	}
+	return 0
+}
+
+// createNonInteractively reads the secrets from their sources and creates the seed file, returning the
+// exit code of the command so that the deferred clean ups run before exiting
+func createNonInteractively() (exitCode int) {
+	if createP.randomSalt || createP.recover {
+		return printCreateResult(createResult{Error: "--randomsalt and --recover can't be used with --non-interactive"})
+	}
+	if createP.masterPasswordFrom == "" || createP.birthdateFrom == "" {
+		return printCreateResult(createResult{Error: "--password-from and --birthdate-from are required with --non-interactive"})
+	}
+	if !createP.force {
+		exists, err := internal.SeedFileExists()
+		if err != nil {
+			return printCreateResult(createResult{Error: "An error occurred checking the seed file: " + err.Error()})
+		}
+		if exists {
+			return printCreateResult(createResult{Error: "The seed file already exists, use --force to overwrite it"})
+		}
+	}
+	var keyfileSHA3 *[32]byte
+	if keyfilePath != "" {
+		var err error
+		keyfileSHA3, err = internal.HashKeyfile(keyfilePath)
+		if err != nil {
+			return printCreateResult(createResult{Error: "An error occurred reading the keyfile: " + err.Error()})
+		}
+		defer internal.ClearByteArray32(keyfileSHA3)
+	}
+	var passphrase *[]byte
+	if createP.passphraseFrom != "" {
+		var err error
+		passphrase, err = internal.ReadSecretFrom(createP.passphraseFrom)
+		if err != nil {
+			return printCreateResult(createResult{Error: "An error occurred reading the passphrase: " + err.Error()})
+		}
+	}
+	masterPassword, err := internal.ReadSecretFrom(createP.masterPasswordFrom)
+	if err != nil {
+		internal.ClearByteSlice(passphrase)
+		return printCreateResult(createResult{Error: "An error occurred reading the master password: " + err.Error()})
+	}
+	birthdate, err := internal.ReadSecretFrom(createP.birthdateFrom)
+	if err != nil {
+		internal.ClearByteSlice(passphrase)
+		internal.ClearByteSlice(masterPassword)
+		return printCreateResult(createResult{Error: "An error occurred reading the birthdate: " + err.Error()})
+	}
+	seedFile, err := internal.CreateNonInteractive(masterPassword, birthdate, createP.defaultUser, passphrase, keyfileSHA3)
+	if err != nil {
+		return printCreateResult(createResult{Error: err.Error()})
+	}
+	return printCreateResult(createResult{
+		Success:     true,
+		Profile:     internal.CurrentProfile(),
+		DefaultUser: seedFile.DefaultUser,
+		Protection:  seedFile.Protection,
+	})
 }
 
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create the seed file",
 	Long: `Create the seed.txt file from your master password and birthdate using Argon2ID. 
+	Your birthdate can be replaced by a random salt given to you as a recovery code with --randomsalt,
+	and the seed can then be recreated from your master password and recovery code with --recover.
 	Optionally (recommended) encrypt your seed.txt file with a randomly generated passphrase.
-	This is forced to be run interactively for security reasons.`,
+	With --non-interactive, the master password, birthdate and optional passphrase are read from
+	environment variables, files or file descriptors instead, i.e. to provision a vault from a script,
+	and the result is printed as JSON.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if createP.nonInteractive {
+			if exitCode := createNonInteractively(); exitCode != 0 {
+				os.Exit(exitCode)
+			}
+			return
+		}
 		fmt.Printf(color.HiWhiteString("Detecting performance of machine for Argon2ID..."))
 		argonTimePerRound := internal.GetArgonTimePerRound()              // depends on the machine
 		fmt.Println(color.HiGreenString("%dms/round", argonTimePerRound)) // TODO in goroutine
-		var masterPasswordSHA3, birthdateSHA3 *[32]byte
-		var protection = "none"
+		var masterPasswordSHA3 *[32]byte
+		var salt *[]byte
+		seedArgon := internal.DefaultSeedArgonParams
 		for {
 			masterPassword, err := internal.ReadSecret("Enter your master password: ")
 			if err != nil {
@@ -44,18 +148,20 @@ var createCmd = &cobra.Command{
 			}
 			safety, message := internal.EvaluatePassword(masterPassword)
 			masterPasswordSHA3 = internal.HashAndDestroy(masterPassword)
-			color.HiWhite(message)
-			if safety == 0 {
-				color.Yellow("Your password is not safe, please enter a more complicated password.")
-				continue
-			} else if safety == 1 {
-				setStrongerPassword := internal.ReadInput("Your password is not very safe, would you like to enter a stronger password? (yes/no) [no]: ")
-				if setStrongerPassword == "yes" {
-					internal.ClearByteArray32(masterPasswordSHA3)
+			if !createP.recover { // an existing master password is not evaluated again
+				color.HiWhite(message)
+				if safety == 0 {
+					color.Yellow("Your password is not safe, please enter a more complicated password.")
 					continue
+				} else if safety == 1 {
+					setStrongerPassword := internal.ReadInput("Your password is not very safe, would you like to enter a stronger password? (yes/no) [no]: ")
+					if setStrongerPassword == "yes" {
+						internal.ClearByteArray32(masterPasswordSHA3)
+						continue
+					}
+				} else {
+					color.HiGreen("Your password is very safe, good job!")
 				}
-			} else {
-				color.HiGreen("Your password is very safe, good job!")
 			}
 			masterPasswordConfirm, err := internal.ReadSecret("Enter your master password again: ")
 			if err != nil {
@@ -72,33 +178,77 @@ var createCmd = &cobra.Command{
 			internal.ClearByteArray32(masterPasswordSHA3Confirm)
 			break
 		}
-		for {
-			birthdate, err := internal.ReadSecret("Enter your date of birth in the format dd/mm/yyyy: ")
-			if err != nil {
-				color.Yellow("An error occurred reading your birthdate: " + err.Error())
-				continue
+		if createP.recover {
+			for {
+				recoveryCode := internal.ReadInput("Enter your recovery code: ")
+				var err error
+				salt, seedArgon, err = internal.DecodeRecoveryCode(recoveryCode)
+				if err != nil {
+					color.Yellow(err.Error() + ", please try again.")
+					continue
+				}
+				color.HiGreen("Your recovery code is valid.")
+				break
 			}
-			if !internal.DateIsValid(birthdate) {
-				color.Yellow("The birthdate you entered is not valid.")
-				internal.ClearByteSlice(birthdate)
-				continue
+		} else if createP.randomSalt {
+			var err error
+			salt, err = internal.MakeSeedSalt(io.ReadFull)
+			if err != nil {
+				color.HiRed("An error occurred generating the random salt: " + err.Error())
+				return
 			}
-			birthdateSHA3 = internal.HashAndDestroy(birthdate)
-			birthdateConfirm, err := internal.ReadSecret("Enter your date of birth in the format dd/mm/yyyy again: ")
+			recoveryCode, err := internal.EncodeRecoveryCode(salt, seedArgon)
 			if err != nil {
-				color.Yellow("An error occurred reading your birthdate confirmation: " + err.Error())
-				internal.ClearByteArray32(birthdateSHA3)
-				continue
+				internal.ClearByteSlice(salt)
+				color.HiRed("An error occurred encoding the recovery code: " + err.Error())
+				return
 			}
-			birthdateSHA3Confirm := internal.HashAndDestroy(birthdateConfirm)
-			if !internal.ByteArrays32Equal(birthdateSHA3, birthdateSHA3Confirm) {
-				color.Yellow("The birthdates entered do not match, please try again.")
+			fmt.Println("Your recovery code is: " + color.HiGreenString(recoveryCode))
+			color.HiYellow("Write it down next to your master password, you will need both to recreate your seed.")
+			for {
+				recoveryCodeConfirm := internal.ReadInput("Enter your recovery code to confirm you wrote it down: ")
+				saltConfirm, _, err := internal.DecodeRecoveryCode(recoveryCodeConfirm)
+				if err != nil || !internal.ByteSlicesEqual(salt, saltConfirm) {
+					internal.ClearByteSlice(saltConfirm)
+					color.Yellow("The recovery code entered does not match, please try again.")
+					continue
+				}
+				internal.ClearByteSlice(saltConfirm)
+				break
+			}
+		} else {
+			var birthdateSHA3 *[32]byte
+			for {
+				birthdate, err := internal.ReadSecret("Enter your date of birth in the format dd/mm/yyyy: ")
+				if err != nil {
+					color.Yellow("An error occurred reading your birthdate: " + err.Error())
+					continue
+				}
+				if !internal.DateIsValid(birthdate) {
+					color.Yellow("The birthdate you entered is not valid.")
+					internal.ClearByteSlice(birthdate)
+					continue
+				}
+				birthdateSHA3 = internal.HashAndDestroy(birthdate)
+				birthdateConfirm, err := internal.ReadSecret("Enter your date of birth in the format dd/mm/yyyy again: ")
+				if err != nil {
+					color.Yellow("An error occurred reading your birthdate confirmation: " + err.Error())
+					internal.ClearByteArray32(birthdateSHA3)
+					continue
+				}
+				birthdateSHA3Confirm := internal.HashAndDestroy(birthdateConfirm)
+				if !internal.ByteArrays32Equal(birthdateSHA3, birthdateSHA3Confirm) {
+					color.Yellow("The birthdates entered do not match, please try again.")
+					internal.ClearByteArray32(birthdateSHA3)
+					internal.ClearByteArray32(birthdateSHA3Confirm)
+					continue
+				}
+				color.HiGreen("Your birthdate is valid.")
+				salt = new([]byte)
+				*salt = append(*salt, birthdateSHA3[:]...)
 				internal.ClearByteArray32(birthdateSHA3)
-				internal.ClearByteArray32(birthdateSHA3Confirm)
-				continue
+				break
 			}
-			color.HiGreen("Your birthdate is valid.")
-			break
 		}
 
 		if createP.defaultUser == "" {
@@ -118,14 +268,14 @@ var createCmd = &cobra.Command{
 		stoppedchan := make(chan struct{})
 		go func() {
 			defer close(stoppedchan)
-			bar := pb.StartNew(int(constants.ArgonTimeCost))
+			bar := pb.StartNew(int(seedArgon.TimeCost))
 			bar.SetRefreshRate(time.Millisecond * 150)
 			bar.ShowCounters = false
 			var i uint32
 			for {
 				select {
 				default:
-					if i == constants.ArgonTimeCost {
+					if i == seedArgon.TimeCost {
 						bar.FinishPrint(color.HiGreenString("About to finish..."))
 						return
 					}
@@ -139,63 +289,27 @@ var createCmd = &cobra.Command{
 			}
 		}()
 		// Launch computation
-		seed := internal.CreateSeed(masterPasswordSHA3, birthdateSHA3) // seed is argonDigestSize bytes long
+		seed := internal.CreateSeedWithSalt(masterPasswordSHA3, *salt, seedArgon) // seed is argonDigestSize bytes long
 
 		// Clean up
 		internal.ClearByteArray32(masterPasswordSHA3)
-		internal.ClearByteArray32(birthdateSHA3)
+		internal.ClearByteSlice(salt)
 		close(stopchan) // stop the progress bar
 		<-stoppedchan   // wait for it to stop
 		color.HiGreen("Seed computed successfully")
+		seedFile := internal.NewSeedFile(createP.defaultUser, seed)
+		seedFile.SeedArgon = seedArgon
+		if createP.randomSalt || createP.recover {
+			seedFile.UseRandomSalt()
+		}
 
-		color.HiWhite("To generate a password, would you like to add one of the following securities:")
-		fmt.Println("A: Randomly picked adjective and noun passphrase " + color.HiGreenString("(recommended)"))
-		fmt.Println("B: Your own passphrase " + color.HiYellowString("(not recommended unless you know what you are doing)"))
-		fmt.Println("C: None " + color.HiRedString("(not recommended unless you seed.txt is safe at all time)"))
-		color.White(`For choices A and B, your seed and identifications database will be encrypted by AES
-using a 256 bit key generated by Argon2ID from the passphrase. You will need that passphrase to do any further operation.`)
-		for {
-			protectionOption := internal.ReadInput("Please enter an additional security option [A]: ")
-			if protectionOption == "A" || protectionOption == "" || protectionOption == "B" {
-				protection = "passphrase"
-				var passphrase string
-				var err error
-				if protectionOption == "A" || protectionOption == "" {
-					for {
-						passphrase, err = internal.MakePassphrase()
-						if err != nil {
-							color.Yellow("An error occurred when generating the passphrase: " + err.Error())
-							continue
-						}
-						fmt.Println("Your generated passphrase is: " + color.HiGreenString(passphrase))
-						anotherOne := internal.ReadInput("Would you prefer another passphrase? (yes/no) [yes]: ")
-						if anotherOne == "" || anotherOne == "yes" {
-							continue
-						} else if anotherOne == "no" {
-							break
-						}
-						color.Yellow("The answer '" + anotherOne + "' is not valid. Please try again.")
-					}
-				} else if protectionOption == "B" {
-					passphrase = internal.ReadInput("Enter your passphrase: ")
-				}
-				passphraseBytes := []byte(passphrase)
-				encryptedSeed, err := internal.EncryptSeed(seed, &passphraseBytes)
-				if err != nil {
-					color.HiRed("The following error occurred when encrypting the seed: " + err.Error())
-					return
-				}
-				internal.ClearByteSlice(seed)
-				seed = encryptedSeed
-				color.HiGreen("Seed encrypted using passphrase successfully.")
-				break
-			} else if protectionOption == "C" {
-				break
-			}
-			color.Yellow("Option '" + protectionOption + "' is not valid. Please try again.")
+		err := protectSeedInteractively(seedFile, seed)
+		if err != nil {
+			color.HiRed("The following error occurred when encrypting the seed: " + err.Error())
+			return
 		}
-		err := internal.WriteSeed(createP.defaultUser, protection, seed)
-		internal.ClearByteSlice(seed)
+		err = internal.WriteSeed(seedFile)
+		internal.ClearByteSlice(seedFile.Seed)
 		if err != nil {
 			color.HiRed("Error writing seed to file: " + err.Error())
 			return
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/atotto/clipboard"
@@ -29,6 +30,21 @@ type generateParams struct {
 	passwordOnly              bool
 	save                      bool
 	passwordDerivationVersion int
+	passwordRules             string
+	siteRules                 bool
+	words                     int
+	separator                 string
+	capitalize                bool
+	pin                       bool
+	rejectWeak                bool
+	readable                  bool
+	pronounceable             bool
+	minEntropy                float64
+	strict                    bool
+	alphabet                  string
+	allow                     string
+	require                   string
+	counts                    []string
 }
 
 var generateP generateParams
@@ -44,12 +60,151 @@ func init() {
 	generateCmd.Flags().BoolVar(&generateP.noUppercase, "nouppercase", false, "Force the password to contain no uppercase letter")
 	generateCmd.Flags().BoolVar(&generateP.noLowercase, "nolowercase", false, "Force the password to contain no lowercase letter")
 	generateCmd.Flags().StringVar(&generateP.excludedCharacters, "exclude", "", "Characters to exclude from the final password")
+	generateCmd.Flags().StringVar(&generateP.allow, "allow", "", "Only use these characters in the password")
+	generateCmd.Flags().StringVar(&generateP.require, "require", "", "Characters that must all appear in the password")
+	generateCmd.Flags().StringArrayVar(&generateP.counts, "count", nil, "Number of characters of a class as class=min-max, i.e. digit=2-4, symbol=1- or uppercase=-3, the class being lowercase, uppercase, digit or symbol (repeatable)")
 	generateCmd.Flags().StringVar(&generateP.note, "note", "", "Extra personal note you want to add")
 	generateCmd.Flags().BoolVar(&generateP.qrcode, "qr", true, "Display the resulting password as a QR code")
 	generateCmd.Flags().BoolVar(&generateP.clipboard, "clipboard", true, "Copy the resulting password to the clipboard")
 	generateCmd.Flags().BoolVar(&generateP.passwordOnly, "passwordonly", false, "Only display the resulting password (for piping)")
 	generateCmd.Flags().BoolVar(&generateP.save, "save", true, "Save the password generation settings and corresponding user to the database")
 	generateCmd.Flags().IntVar(&generateP.passwordDerivationVersion, "version", constants.PasswordDerivationVersion, "Version of the core password generation code to be used")
+	generateCmd.Flags().StringVar(&generateP.passwordRules, "rules", "", "Password requirements of the website in the passwordrules syntax, i.e. 'minlength: 8; maxlength: 16; required: lower; required: [-!]'")
+	generateCmd.Flags().IntVar(&generateP.words, "words", 0, "Generate a passphrase of this number of words from the BIP39 English list (11 bits each) instead of characters, i.e. for Wi-Fi keys or passwords typed on a TV")
+	generateCmd.Flags().StringVar(&generateP.separator, "separator", "-", "Separator between the words of a passphrase generated with --words")
+	generateCmd.Flags().BoolVar(&generateP.capitalize, "capitalize", false, "Capitalize the words of a passphrase generated with --words")
+	generateCmd.Flags().BoolVar(&generateP.pin, "pin", false, "Generate a numeric code of --length digits, 6 by default, i.e. for a phone carrier, a bank card or a door")
+	generateCmd.Flags().BoolVar(&generateP.rejectWeak, "rejectweak", true, "Reject codes generated with --pin made of repeated digits, sequences or dates")
+	generateCmd.Flags().BoolVar(&generateP.readable, "readable", false, "Exclude the characters that can be confused when read, such as l, 1, I, O and 0")
+	generateCmd.Flags().BoolVar(&generateP.pronounceable, "pronounceable", false, "Generate a password of syllables that can be read aloud, of "+strconv.Itoa(constants.DefaultPronounceableLength)+" characters by default")
+	generateCmd.Flags().StringVar(&generateP.alphabet, "alphabet", "", "Generate the password with the characters of an alphabet only: hex, base32, base58, base64url, alphanumeric or the characters given, i.e. for tokens or keys")
+	generateCmd.Flags().Float64Var(&generateP.minEntropy, "minentropy", constants.MinimumPasswordEntropyBits, "Warn if the password has less bits of entropy, not applied to codes generated with --pin unless given")
+	generateCmd.Flags().BoolVar(&generateP.strict, "strict", false, "Refuse to generate a password with less bits of entropy than --minentropy")
+	generateCmd.Flags().BoolVar(&generateP.siteRules, "siterules", true, "Apply the known password rules of the website to a new website generated without generation flags")
+}
+
+// generationFlagsChanged returns true if any flag changing the generated password was given
+func generationFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range []string{"length", "nosymbol", "nodigit", "nouppercase", "nolowercase", "exclude", "allow", "require", "count", "version", "rules", "words", "separator", "capitalize", "pin", "rejectweak", "readable", "pronounceable", "alphabet"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// passwordModeFromFlags returns the password mode and its options given by the flags, displaying an error if the flags are not compatible
+func passwordModeFromFlags(cmd *cobra.Command) (passwordMode, modeOptions string, ok bool) {
+	var modeFlags []string
+	for _, name := range []string{"words", "pin", "readable", "pronounceable", "alphabet"} {
+		if cmd.Flags().Changed(name) && cmd.Flags().Lookup(name).Value.String() != cmd.Flags().Lookup(name).DefValue {
+			modeFlags = append(modeFlags, "--"+name)
+		}
+	}
+	if len(modeFlags) > 1 {
+		color.HiRed(strings.Join(modeFlags, " and ") + " can't be used together")
+		return "", "", false
+	}
+	characterFlags := []string{"rules", "nosymbol", "nodigit", "nouppercase", "nolowercase", "exclude", "allow", "require", "count"}
+	switch {
+	case generateP.words != 0:
+		if generateP.words < 1 || generateP.words > 255 {
+			color.HiRed("The number of words must be between 1 and 255")
+			return "", "", false
+		}
+		characterFlags = append(characterFlags, "length")
+		passwordMode = internal.PasswordModeWords
+		modeOptions = internal.WordsOptionsType{Separator: generateP.separator, Capitalize: generateP.capitalize}.String()
+		generateP.passwordLength = generateP.words
+	case generateP.pin:
+		if !cmd.Flags().Changed("length") {
+			generateP.passwordLength = constants.DefaultPinLength
+		}
+		if generateP.passwordLength < 4 || generateP.passwordLength > 255 {
+			color.HiRed("The length of a code must be between 4 and 255 digits")
+			return "", "", false
+		}
+		passwordMode = internal.PasswordModePin
+		modeOptions = internal.PinOptionsType{RejectWeak: generateP.rejectWeak}.String()
+	case generateP.readable:
+		characterFlags = nil
+		passwordMode = internal.PasswordModeReadable
+	case generateP.pronounceable:
+		if !cmd.Flags().Changed("length") {
+			generateP.passwordLength = constants.DefaultPronounceableLength
+		}
+		passwordMode = internal.PasswordModePronounceable
+	case generateP.alphabet != "":
+		alphabet, err := internal.ResolveAlphabet(generateP.alphabet)
+		if err != nil {
+			color.HiRed(err.Error())
+			return "", "", false
+		}
+		passwordMode = internal.PasswordModeAlphabet
+		modeOptions = internal.AlphabetOptionsType{Alphabet: alphabet}.String()
+	}
+	if passwordMode == internal.PasswordModeCharacters {
+		for name, modeFlag := range map[string]string{"separator": "words", "capitalize": "words", "rejectweak": "pin"} {
+			if cmd.Flags().Changed(name) {
+				color.HiRed("--" + name + " requires --" + modeFlag)
+				return "", "", false
+			}
+		}
+		return passwordMode, "", true
+	}
+	if generateP.passwordDerivationVersion < 4 {
+		color.HiRed("The password mode '" + passwordMode + "' requires the password derivation version 4 or above")
+		return "", "", false
+	}
+	for _, name := range characterFlags {
+		if cmd.Flags().Changed(name) {
+			color.HiRed("The password mode '" + passwordMode + "' can't be used with --" + name)
+			return "", "", false
+		}
+	}
+	if (passwordMode != internal.PasswordModeWords && (cmd.Flags().Changed("separator") || cmd.Flags().Changed("capitalize"))) ||
+		(passwordMode != internal.PasswordModePin && cmd.Flags().Changed("rejectweak")) {
+		color.HiRed("The options given can't be used with the password mode '" + passwordMode + "'")
+		return "", "", false
+	}
+	return passwordMode, modeOptions, true
+}
+
+// characterConstraintsFromFlags returns the character constraints given by the flags, displaying an error if they can't be satisfied
+func characterConstraintsFromFlags() (constraints internal.CharacterConstraintsType, ok bool) {
+	constraints = internal.NewCharacterConstraints(generateP.noSymbol, generateP.noDigit, generateP.noUppercase, generateP.noLowercase, generateP.excludedCharacters)
+	constraints.Allowed = generateP.allow
+	constraints.Required = generateP.require
+	for _, count := range generateP.counts {
+		class, classCount, err := internal.ParseClassCount(count)
+		if err != nil {
+			color.HiRed(err.Error())
+			return constraints, false
+		}
+		if constraints.Counts == nil {
+			constraints.Counts = make(map[string]internal.ClassCountType)
+		}
+		constraints.Counts[class] = classCount
+	}
+	constraints = constraints.Canonical()
+	if !constraints.OnlyForbids() && generateP.passwordDerivationVersion < 4 {
+		color.HiRed("--allow, --require and --count require the password derivation version 4 or above")
+		return constraints, false
+	}
+	if generateP.passwordRules != "" && (constraints.Required != "" || len(constraints.Counts) > 0) {
+		color.HiRed("--require and --count can't be used with --rules")
+		return constraints, false
+	}
+	if !constraints.IsAnythingAllowed() {
+		color.HiRed("The password can't be generated with all possible characters excluded")
+		return constraints, false
+	}
+	err := constraints.Validate()
+	if err != nil {
+		color.HiRed("The character constraints can't be satisfied: " + err.Error())
+		return constraints, false
+	}
+	return constraints, true
 }
 
 var generateCmd = &cobra.Command{
@@ -59,19 +214,54 @@ var generateCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		website := args[0]
-		unallowedCharacters := internal.BuildUnallowedCharacters(generateP.noSymbol, generateP.noDigit, generateP.noUppercase, generateP.noLowercase, generateP.excludedCharacters)
-		if !unallowedCharacters.IsAnythingAllowed() {
-			color.HiRed("The password can't be generated with all possible characters excluded")
+		if generateP.passwordDerivationVersion < 1 || generateP.passwordDerivationVersion > constants.PasswordDerivationVersion {
+			color.HiRed("The password derivation version must be between 1 and " + strconv.Itoa(constants.PasswordDerivationVersion))
+			return
+		}
+		var passwordRules string
+		if generateP.passwordRules != "" {
+			if generateP.passwordDerivationVersion < 4 {
+				color.HiRed("Password rules require the password derivation version 4 or above")
+				return
+			}
+			rules, err := internal.ParsePasswordRules(generateP.passwordRules)
+			if err != nil {
+				color.HiRed("The password rules are invalid: " + err.Error())
+				return
+			}
+			if !cmd.Flags().Changed("length") {
+				generateP.passwordLength = rules.ClampLength(generateP.passwordLength)
+			} else if rules.ClampLength(generateP.passwordLength) != generateP.passwordLength {
+				color.HiRed("The password length " + strconv.Itoa(generateP.passwordLength) + " does not satisfy the password rules")
+				return
+			}
+			passwordRules = rules.String()
+		}
+		passwordMode, modeOptions, ok := passwordModeFromFlags(cmd)
+		if !ok {
+			return
+		}
+		if generateP.passwordLength < 1 || generateP.passwordLength > constants.MaxPasswordLength {
+			color.HiRed("The password length must be between 1 and " + strconv.Itoa(constants.MaxPasswordLength))
+			return
+		}
+		if generateP.passwordLength > 255 && generateP.passwordDerivationVersion < 4 {
+			color.HiRed("Passwords longer than 255 characters require the password derivation version 4 or above")
 			return
 		}
-		defaultUser, protection, seed, err := internal.ReadSeed()
+		constraints, ok := characterConstraintsFromFlags()
+		if !ok {
+			return
+		}
+		seedFile, err := internal.ReadSeed()
 		if err != nil {
 			color.Yellow("An error occurred reading the seed file: " + err.Error())
 			return
 		}
+		seed := seedFile.Seed
 
 		userIsDefault := true
-		user := defaultUser
+		user := seedFile.DefaultUser
 		if generateP.user != "" { // user flag provided
 			user = generateP.user
 			userIsDefault = false
@@ -84,35 +274,30 @@ var generateCmd = &cobra.Command{
 			color.Yellow("Please enter a non empty user.")
 		}
 
-		if protection == "passphrase" { // TODO encrypt/decrypt SQLite
-			for {
-				passphraseBytesPtr, err := internal.ReadSecret("Enter your passphrase to decrypt the seed: ")
-				if err != nil {
-					color.Yellow("An error occurred reading the passphrase: " + err.Error())
-					continue
-				}
-				decryptedSeed, err := internal.DecryptSeed(seed, passphraseBytesPtr)
-				internal.ClearByteSlice(passphraseBytesPtr)
-				if err != nil {
-					internal.ClearByteSlice(decryptedSeed)
-					color.HiRed("Seed or passphrase is invalid: " + err.Error())
-					continue
-				}
-				internal.ClearByteSlice(seed)
-				seed = decryptedSeed
-				break
-			}
+		if seedFile.IsProtected() { // TODO encrypt/decrypt SQLite
+			seed = decryptSeedInteractively(seedFile)
+		}
+		err = seedFile.CheckWebsiteNamespace(website)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		if seedFile.NeedsUpgrade() {
+			color.HiYellow("Your " + constants.SeedFilename + " uses an older format, you should run 'derivatex upgrade'")
 		}
 
 		newIdentification := internal.IdentificationType{
 			Website:                   website,
 			User:                      user,
-			PasswordLength:            uint8(generateP.passwordLength),
+			PasswordLength:            uint16(generateP.passwordLength),
 			Round:                     uint16(generateP.round),
-			UnallowedCharacters:       unallowedCharacters.Serialize(),
+			Constraints:               constraints,
 			CreationTime:              time.Now().Unix(), // set to previous database record if a record is found
 			PasswordDerivationVersion: uint16(generateP.passwordDerivationVersion),
-			Note: generateP.note,
+			Note:                      generateP.note,
+			PasswordRules:             passwordRules,
+			PasswordMode:              passwordMode,
+			ModeOptions:               modeOptions,
 		}
 		identificationIsNew := true
 		identificationExists := false
@@ -122,6 +307,23 @@ var generateCmd = &cobra.Command{
 			color.HiRed("Error reading the database file '" + constants.DatabaseFilename + "' (" + err.Error() + ")")
 			return
 		}
+		if len(identifications) == 0 && generateP.siteRules && !generationFlagsChanged(cmd) {
+			siteRules, found, err := internal.FindSiteRules(internal.StripWebsiteNamespace(website))
+			if err != nil {
+				color.HiRed("Error reading the site rules (" + err.Error() + ")")
+				return
+			}
+			if found {
+				rules, err := internal.ParsePasswordRules(siteRules.PasswordRules)
+				if err != nil {
+					color.HiRed("The password rules of " + siteRules.Domain + " are invalid: " + err.Error())
+					return
+				}
+				newIdentification.PasswordLength = uint16(rules.ClampLength(int(newIdentification.PasswordLength)))
+				newIdentification.PasswordRules = siteRules.PasswordRules
+				color.HiWhite("Using the " + siteRules.Source + " password rules of " + siteRules.Domain + ": " + siteRules.PasswordRules)
+			}
+		}
 
 		if len(identifications) == 0 {
 			identificationIsNew = true
@@ -142,6 +344,7 @@ var generateCmd = &cobra.Command{
 						replaceOrOld := internal.ReadInput("Replace the old identification or generate using the old identification? (replace/old) [old]: ")
 						if replaceOrOld == "replace" {
 							replaceIdentification = true
+							newIdentification.KeepOTP(&existingIdentification)
 							identificationIsNew = true
 							break
 						} else if replaceOrOld == "old" || replaceOrOld == "" {
@@ -182,6 +385,7 @@ var generateCmd = &cobra.Command{
 					replaceOrOld := internal.ReadInput("Replace the old identification or generate using the old identification? (replace/old) [old]: ")
 					if replaceOrOld == "replace" {
 						replaceIdentification = true
+						newIdentification.KeepOTP(&existingIdentification)
 						identificationIsNew = true
 						break
 					} else if replaceOrOld == "old" || replaceOrOld == "" {
@@ -229,14 +433,36 @@ var generateCmd = &cobra.Command{
 			}
 		}
 
-		if newIdentification.PasswordDerivationVersion != constants.PasswordDerivationVersion {
+		if internal.IsPasswordDerivationOutdated(newIdentification.Website, newIdentification.PasswordDerivationVersion) {
 			color.HiYellow("This password is generated using the derivation program version " + strconv.FormatUint(uint64(newIdentification.PasswordDerivationVersion), 10) + ", you should change it using the latest version " + strconv.FormatUint(uint64(constants.PasswordDerivationVersion), 10) + " of the current program")
 		}
 
-		var password string
-		passwordDigest := internal.MakePasswordDigest(seed, newIdentification.Website, newIdentification.User, newIdentification.PasswordDerivationVersion)
-		unallowedCharacters = internal.BuildUnallowedCharacters(false, false, false, false, newIdentification.UnallowedCharacters)
-		password = internal.SatisfyPassword(passwordDigest, newIdentification.PasswordLength, newIdentification.Round, unallowedCharacters, newIdentification.PasswordDerivationVersion)
+		entropy, err := newIdentification.Entropy()
+		if err != nil {
+			color.HiRed("The password can't be generated: " + err.Error())
+			return
+		}
+		if entropy < generateP.minEntropy && (newIdentification.PasswordMode != internal.PasswordModePin || cmd.Flags().Changed("minentropy")) {
+			message := "The password has only " + internal.FormatEntropy(entropy) + " of entropy, less than the minimum of " + internal.FormatEntropy(generateP.minEntropy)
+			if generateP.strict {
+				color.HiRed(message + ", use a longer password or allow more characters")
+				return
+			}
+			color.HiYellow(message)
+		}
+
+		websiteSeed, err := seedFile.WebsiteSeed(seed, newIdentification.Website, newIdentification.PasswordDerivationVersion)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		defer internal.ClearByteSlice(websiteSeed)
+		passwordDigest := internal.MakePasswordDigest(websiteSeed, newIdentification.Website, newIdentification.User, newIdentification.PasswordDerivationVersion)
+		password, err := internal.GeneratePassword(passwordDigest, &newIdentification)
+		if err != nil {
+			color.HiRed("The password can't be generated: " + err.Error())
+			return
+		}
 		color.White("Using the following identification to generate the password:")
 		internal.DisplayIdentificationCLI(newIdentification)
 		if generateP.save {
@@ -269,6 +495,7 @@ var generateCmd = &cobra.Command{
 			qrterminal.GenerateWithConfig(password, config)
 		}
 		fmt.Println(color.HiGreenString("User: ") + color.HiWhiteString(newIdentification.User))
+		fmt.Println(color.HiGreenString("Entropy: ") + color.HiWhiteString(internal.FormatEntropy(entropy)))
 		fmt.Println(color.HiGreenString("Password: ") + color.HiWhiteString(password))
 		if generateP.clipboard {
 			clipboard.WriteAll(password)
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mdp/qrterminal"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/constants"
+	"github.com/techsek/derivatex/internal"
+)
+
+type otpParams struct {
+	user         string
+	derive       bool
+	importSecret bool
+	importFrom   string
+	hotp         bool
+	algorithm    string
+	digits       int
+	period       int
+	round        int
+	uri          bool
+	qrcode       bool
+}
+
+var otpP otpParams
+
+func init() {
+	rootCmd.AddCommand(otpCmd)
+
+	otpCmd.Flags().StringVar(&otpP.user, "user", "", "Email, username or phone number the one time passwords are used with")
+	otpCmd.Flags().BoolVar(&otpP.derive, "derive", false, "Derive the secret from the seed and display it to set up a service you control")
+	otpCmd.Flags().BoolVar(&otpP.importSecret, "import", false, "Import the otpauth:// URI or base32 secret given by a service, entered hidden interactively and stored encrypted in the database")
+	otpCmd.Flags().StringVar(&otpP.importFrom, "import-from", "", "Import the otpauth:// URI or base32 secret given by a service, read from env:NAME, file:PATH or fd:N")
+	otpCmd.Flags().BoolVar(&otpP.hotp, "hotp", false, "Use counter based one time passwords (HOTP) instead of time based ones (TOTP)")
+	otpCmd.Flags().StringVar(&otpP.algorithm, "algorithm", "SHA1", "HMAC algorithm of the codes: SHA1, SHA256 or SHA512")
+	otpCmd.Flags().IntVar(&otpP.digits, "digits", 6, "Number of digits of the codes, between 6 and 8")
+	otpCmd.Flags().IntVar(&otpP.period, "period", 30, "Seconds during which a time based code is valid")
+	otpCmd.Flags().IntVar(&otpP.round, "round", 1, "Make higher than 1 to renew the derived secret")
+	otpCmd.Flags().BoolVar(&otpP.uri, "uri", false, "Display the otpauth:// URI of the secret to add it to another authenticator")
+	otpCmd.Flags().BoolVar(&otpP.qrcode, "qr", true, "Display the otpauth:// URI as a QR code when it is displayed")
+}
+
+// readOTPSecret reads the otpauth:// URI or base32 secret to import from the source, or hidden interactively
+// so that it doesn't appear in the arguments of the process or in the shell history
+func readOTPSecret(source string) (secret *[]byte, err error) {
+	if source != "" {
+		return internal.ReadSecretFrom(source)
+	}
+	return internal.ReadSecret("Enter the otpauth:// URI or base32 secret given by the service: ")
+}
+
+// otpOptionsFromFlags returns the one time password type and options, the flags given replacing the type and options given
+func otpOptionsFromFlags(cmd *cobra.Command, otpType string, options internal.OTPOptionsType) (string, internal.OTPOptionsType, bool) {
+	if cmd.Flags().Changed("hotp") {
+		otpType = internal.OTPTypeTOTP
+		if otpP.hotp {
+			otpType = internal.OTPTypeHOTP
+		}
+		options.Period = internal.DefaultOTPOptions(otpType).Period
+	}
+	if cmd.Flags().Changed("algorithm") {
+		options.Algorithm = strings.ToUpper(otpP.algorithm)
+	}
+	if cmd.Flags().Changed("digits") {
+		options.Digits = otpP.digits
+	}
+	if cmd.Flags().Changed("period") {
+		options.Period = otpP.period
+	}
+	if otpType == internal.OTPTypeHOTP && cmd.Flags().Changed("period") {
+		color.HiRed("--period can't be used with --hotp")
+		return otpType, options, false
+	}
+	err := options.Validate(otpType)
+	if err != nil {
+		color.HiRed(err.Error())
+		return otpType, options, false
+	}
+	return otpType, options, true
+}
+
+// displayOTPURI displays the otpauth URI of the secret and its QR code
+func displayOTPURI(identification internal.IdentificationType, secret []byte, options internal.OTPOptionsType) {
+	uri := internal.OTPAuthURI(identification.OTPType, identification.Website, identification.User, secret, options)
+	if otpP.qrcode {
+		color.HiGreen("OTP QR Code:")
+		config := qrterminal.Config{
+			Level:     qrterminal.M,
+			Writer:    os.Stdout,
+			BlackChar: qrterminal.WHITE,
+			WhiteChar: qrterminal.BLACK,
+			QuietZone: 1,
+		}
+		qrterminal.GenerateWithConfig(uri, config)
+	}
+	fmt.Println(color.HiGreenString("URI: ") + color.HiWhiteString(uri))
+}
+
+var otpCmd = &cobra.Command{
+	Use:   "otp <websitename>",
+	Short: "Display the one time password of a website",
+	Long: `Display the current one time password of a website and user, as specified by RFC 6238 (TOTP)
+	or RFC 4226 (HOTP). The secret is either derived from the seed with --derive, for services you control,
+	or imported with --import and stored encrypted with a key derived from the seed in the database.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		website := args[0]
+		importing := otpP.importSecret || otpP.importFrom != ""
+		if otpP.derive && importing {
+			color.HiRed("--derive and --import can't be used together")
+			return
+		}
+		setUp := otpP.derive || importing
+		if !setUp {
+			for _, name := range []string{"hotp", "algorithm", "digits", "period", "round"} {
+				if cmd.Flags().Changed(name) {
+					color.HiRed("--" + name + " requires --derive or --import")
+					return
+				}
+			}
+		}
+		if otpP.round < 1 || otpP.round > 65535 {
+			color.HiRed("The round must be between 1 and 65535")
+			return
+		}
+		seedFile, err := internal.ReadSeed()
+		if err != nil {
+			color.Yellow("An error occurred reading the seed file: " + err.Error())
+			return
+		}
+		err = seedFile.CheckWebsiteNamespace(website)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		user := seedFile.DefaultUser
+		if otpP.user != "" {
+			user = otpP.user
+		}
+		for user == "" {
+			user = internal.ReadInput("User of the one time passwords: ")
+			if user == "" {
+				color.Yellow("Please enter a non empty user.")
+			}
+		}
+		identification, err := internal.FindIdentification(website, user)
+		if err != nil {
+			color.HiRed("Error reading the database file '" + constants.DatabaseFilename + "' (" + err.Error() + ")")
+			return
+		}
+		identificationExists := identification.Website != ""
+		if !setUp && identification.OTPType == internal.OTPTypeNone {
+			color.HiRed("No one time password is set for '" + website + "' and user '" + user + "', use --derive or --import")
+			return
+		}
+		if setUp && identification.OTPType != internal.OTPTypeNone {
+			replace := internal.ReadInput("A one time password is already set for '" + website + "' and user '" + user + "', do you want to replace it? (yes/no) [no]: ")
+			if replace != "yes" {
+				return
+			}
+		}
+
+		var otpType string
+		var options internal.OTPOptionsType
+		var importedSecret []byte
+		if otpP.derive {
+			otpType = internal.OTPTypeTOTP
+			options = internal.DefaultOTPOptions(otpType)
+			options.Round = uint16(otpP.round)
+		} else if importing {
+			if cmd.Flags().Changed("round") {
+				color.HiRed("--round requires --derive")
+				return
+			}
+			secret, err := readOTPSecret(otpP.importFrom)
+			if err != nil {
+				color.HiRed("An error occurred reading the secret to import: " + err.Error())
+				return
+			}
+			otpType, importedSecret, options, err = internal.ParseOTPSecret(string(*secret))
+			internal.ClearByteSlice(secret)
+			if err != nil {
+				color.HiRed(err.Error())
+				return
+			}
+			defer internal.ClearByteSlice(&importedSecret)
+		}
+		if setUp {
+			var ok bool
+			otpType, options, ok = otpOptionsFromFlags(cmd, otpType, options)
+			if !ok {
+				return
+			}
+		}
+
+		seed := seedFile.Seed
+		if seedFile.IsProtected() {
+			seed = decryptSeedInteractively(seedFile)
+		}
+		passwordDerivationVersion := uint16(constants.PasswordDerivationVersion)
+		if identificationExists {
+			passwordDerivationVersion = identification.PasswordDerivationVersion
+		}
+		websiteSeed, err := seedFile.WebsiteSeed(seed, website, passwordDerivationVersion)
+		internal.ClearByteSlice(seed)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		defer internal.ClearByteSlice(websiteSeed)
+
+		if setUp {
+			if !identificationExists { // with the default password generation settings
+				identification = internal.IdentificationType{
+					Website:                   website,
+					User:                      user,
+					PasswordLength:            constants.DefaultPasswordLength,
+					Round:                     1,
+					CreationTime:              time.Now().Unix(),
+					PasswordDerivationVersion: constants.PasswordDerivationVersion,
+				}
+			}
+			identification.OTPType = otpType
+			identification.OTPOptions = options.String()
+			identification.OTPSecret = ""
+			if importedSecret != nil {
+				err = identification.SetImportedOTPSecret(websiteSeed, importedSecret)
+				if err != nil {
+					color.HiRed("Error encrypting the OTP secret: " + err.Error())
+					return
+				}
+			}
+			if identificationExists {
+				err = internal.UpdateIdentificationOTP(identification)
+			} else {
+				err = internal.InsertIdentification(identification)
+			}
+			if err != nil {
+				color.HiRed("Error saving the one time password in the database: " + err.Error())
+				return
+			}
+			color.HiGreen("One time password settings saved in database.")
+		}
+
+		options, err = internal.ParseOTPOptions(identification.OTPOptions)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		secret, err := identification.OTPSecretFromSeed(websiteSeed)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		defer internal.ClearByteSlice(&secret)
+		if otpP.derive || otpP.uri {
+			displayOTPURI(identification, secret, options)
+		}
+		fmt.Println(color.HiGreenString("User: ") + color.HiWhiteString(identification.User))
+		if identification.OTPType == internal.OTPTypeHOTP {
+			code, err := internal.HOTPCode(secret, options.Counter, options)
+			if err != nil {
+				color.HiRed(err.Error())
+				return
+			}
+			counter := options.Counter
+			options.Counter++
+			identification.OTPOptions = options.String()
+			err = internal.UpdateIdentificationOTP(identification)
+			if err != nil {
+				color.HiRed("Error saving the counter in the database: " + err.Error())
+				return
+			}
+			fmt.Println(color.HiGreenString("Counter: ") + color.HiWhiteString(strconv.FormatUint(counter, 10)))
+			fmt.Println(color.HiGreenString("Code: ") + color.HiWhiteString(code))
+			return
+		}
+		code, remaining, err := internal.TOTPCode(secret, time.Now(), options)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		fmt.Println(color.HiGreenString("Code: ") + color.HiWhiteString(code))
+		fmt.Println(color.HiGreenString("Expires in: ") + color.HiWhiteString(strconv.Itoa(int(remaining.Seconds()))+"s"))
+	},
+}
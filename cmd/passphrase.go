@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+)
+
+func init() {
+	rootCmd.AddCommand(passphraseCmd)
+	passphraseCmd.AddCommand(passphraseChangeCmd)
+}
+
+var passphraseCmd = &cobra.Command{
+	Use:   "passphrase",
+	Short: "Manage the passphrase protecting the seed file",
+	Long:  `Manage the passphrase protecting the seed file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var passphraseChangeCmd = &cobra.Command{
+	Use:   "change",
+	Short: "Change the passphrase protecting the seed file",
+	Long: `Decrypt the seed with your current passphrase and encrypt it again with a new passphrase,
+	without having to compute the seed again from your master password and birthdate.
+	This can also add a passphrase to an unprotected seed or remove the passphrase.
+	This is forced to be run interactively for security reasons.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		seedFile, seed, err := readDecryptedSeed()
+		if err != nil {
+			color.Yellow("An error occurred reading the seed file: " + err.Error())
+			return
+		}
+		err = protectSeedInteractively(seedFile, seed)
+		if err != nil {
+			color.HiRed("The following error occurred when encrypting the seed: " + err.Error())
+			return
+		}
+		err = internal.WriteSeed(seedFile)
+		internal.ClearByteSlice(seedFile.Seed)
+		if err != nil {
+			color.HiRed("Error writing seed to file: " + err.Error())
+			return
+		}
+		color.HiGreen("Seed saved successfully!")
+	},
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+)
+
+type pgpKeyParams struct {
+	uid            string
+	output         string
+	passphrase     bool
+	passphraseFrom string
+	force          bool
+}
+
+var pgpKeyP pgpKeyParams
+
+func init() {
+	rootCmd.AddCommand(pgpKeyCmd)
+
+	pgpKeyCmd.Flags().StringVar(&pgpKeyP.uid, "uid", "", "User ID of the key such as \"Name <email>\", required for a new key")
+	pgpKeyCmd.Flags().StringVar(&pgpKeyP.output, "output", "", "Prefix of the files of the keys, the secret key being written to <prefix>.sec.asc and the public key to <prefix>.pub.asc, defaults to pgp_<label>")
+	pgpKeyCmd.Flags().BoolVar(&pgpKeyP.passphrase, "passphrase", false, "Encrypt the secret key with a passphrase entered interactively")
+	pgpKeyCmd.Flags().StringVar(&pgpKeyP.passphraseFrom, "passphrase-from", "", "Encrypt the secret key with the passphrase read from env:NAME, file:PATH or fd:N")
+	pgpKeyCmd.Flags().BoolVar(&pgpKeyP.force, "force", false, "Overwrite existing key files")
+}
+
+var pgpKeyCmd = &cobra.Command{
+	Use:   "pgp-key [label]",
+	Short: "Derive an OpenPGP key from the seed",
+	Long: `Derive an OpenPGP key of a label, which defaults to the user ID, from the seed and write its armored
+	secret and public keys. The key is made of an Ed25519 primary key to certify and sign, and of a Curve25519
+	subkey to encrypt. The label, the user ID, the derivation version and the creation time are saved in the
+	database so that the key, and its fingerprint, can be derived again identically on any machine with the seed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		label := pgpKeyP.uid
+		if len(args) == 1 {
+			label = args[0]
+		}
+		if label == "" {
+			color.HiRed("A user ID is required with --uid, such as --uid \"Name <email>\"")
+			return
+		}
+		key, found, err := internal.FindKeyIdentification(internal.KeyTypePGP, label)
+		if err != nil {
+			color.HiRed("Error reading the database (" + err.Error() + ")")
+			return
+		}
+		if !found {
+			if pgpKeyP.uid == "" {
+				color.HiRed("No OpenPGP key is saved with the label " + label + ", a user ID is required with --uid to create it")
+				return
+			}
+			key = internal.KeyIdentificationType{
+				Type:              internal.KeyTypePGP,
+				Label:             label,
+				DerivationVersion: internal.PGPKeyDerivationVersion,
+				CreationTime:      time.Now().Unix(),
+				UserID:            pgpKeyP.uid,
+			}
+		} else {
+			if key.DerivationVersion != internal.PGPKeyDerivationVersion {
+				color.HiYellow("This key is derived using the OpenPGP key derivation version " + strconv.FormatUint(uint64(key.DerivationVersion), 10) + ", the latest version being " + strconv.Itoa(internal.PGPKeyDerivationVersion))
+			}
+			if pgpKeyP.uid != "" && pgpKeyP.uid != key.UserID {
+				color.HiYellow("The user ID changes from " + key.UserID + " to " + pgpKeyP.uid + ", the fingerprint of the key stays the same")
+				key.UserID = pgpKeyP.uid
+			}
+		}
+		output := pgpKeyP.output
+		if output == "" {
+			output = keyFilename("pgp_", label)
+		}
+
+		passphrase, err := readKeyPassphrase(pgpKeyP.passphrase, pgpKeyP.passphraseFrom)
+		if err != nil {
+			color.HiRed("An error occurred reading the passphrase: " + err.Error())
+			return
+		}
+		defer internal.ClearByteSlice(passphrase)
+		seedFile, seed, err := readDecryptedSeed()
+		if err != nil {
+			color.Yellow("An error occurred reading the seed file: " + err.Error())
+			return
+		}
+		if seedFile.IsChild() {
+			internal.ClearByteSlice(seed)
+			color.HiRed("A child seed only generates the passwords of its namespace " + seedFile.Namespace + ", please derive keys with its parent seed")
+			return
+		}
+		pgpKey, err := internal.DerivePGPKey(seed, key.Label, key.UserID, key.CreationTime, key.DerivationVersion)
+		internal.ClearByteSlice(seed)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		defer pgpKey.Clear()
+		privateKey, err := pgpKey.ArmoredPrivateKey(passphrase, io.ReadFull)
+		if err != nil {
+			color.HiRed("Error encoding the secret key: " + err.Error())
+			return
+		}
+		defer internal.ClearByteSlice(&privateKey)
+		publicKey, err := pgpKey.ArmoredPublicKey()
+		if err != nil {
+			color.HiRed("Error encoding the public key: " + err.Error())
+			return
+		}
+		secretFile, publicFile := output+".sec.asc", output+".pub.asc"
+		files := map[string][]byte{secretFile: privateKey, publicFile: publicKey}
+		if !writeKeyFiles(pgpKeyP.force, files, map[string]os.FileMode{secretFile: 0600, publicFile: 0644}) {
+			return
+		}
+		err = internal.InsertKeyIdentification(key)
+		if err != nil {
+			color.HiRed("Error saving the key in the database: " + err.Error())
+			return
+		}
+		if !found {
+			color.HiGreen("New OpenPGP key label saved in database.")
+		}
+		fmt.Println(color.HiGreenString("Secret key: ") + color.HiWhiteString(secretFile))
+		fmt.Println(color.HiGreenString("Public key: ") + color.HiWhiteString(publicFile))
+		fmt.Println(color.HiGreenString("User ID: ") + color.HiWhiteString(key.UserID))
+		fmt.Println(color.HiGreenString("Created: ") + color.HiWhiteString(time.Unix(key.CreationTime, 0).UTC().Format(time.RFC3339)))
+		fmt.Println(color.HiGreenString("Fingerprint: ") + color.HiWhiteString(pgpKey.Fingerprint()))
+		fmt.Print(string(publicKey))
+	},
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+)
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileUseCmd)
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage the profiles of the vault",
+	Long: `Manage the profiles of the vault, each profile having its own seed and database files,
+	i.e. to separate work and personal identities.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the profiles of the vault",
+	Long:  `List the profiles of the vault, the profile in use being marked with a star.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := internal.ListProfiles()
+		if err != nil {
+			color.HiRed("Error listing the profiles (" + err.Error() + ")")
+			return
+		}
+		for _, profile := range profiles {
+			if profile == internal.CurrentProfile() {
+				fmt.Println(color.HiGreenString("* " + profile))
+			} else {
+				fmt.Println("  " + profile)
+			}
+		}
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new profile in the vault",
+	Long: `Create a new empty profile in the vault. Select it with 'derivatex profile use <name>'
+	or for a single command with --profile <name>, then create its seed with 'derivatex create'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := internal.CreateProfile(args[0])
+		if err != nil {
+			color.HiRed("Error creating the profile (" + err.Error() + ")")
+			return
+		}
+		color.HiGreen("Profile " + args[0] + " created successfully.")
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Select the profile used by the next commands",
+	Long:  `Select the profile used by the next commands, until another profile is selected.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := internal.UseProfile(args[0])
+		if err != nil {
+			color.HiRed("Error selecting the profile (" + err.Error() + ")")
+			return
+		}
+		color.HiGreen("Profile " + args[0] + " is now in use.")
+	},
+}
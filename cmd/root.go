@@ -4,19 +4,62 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/constants"
+	"github.com/techsek/derivatex/internal"
 )
 
+// keyfilePath is the keyfile protecting the seed, given to any command with --keyfile
+var keyfilePath string
+
+type rootParams struct {
+	vault   string
+	profile string
+}
+
+var rootP rootParams
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&keyfilePath, "keyfile", "", "File used as an additional factor to protect the seed, i.e. on a USB stick")
+	rootCmd.PersistentFlags().StringVar(&rootP.vault, "vault", "", "Directory containing the seed and database files, defaults to $"+constants.VaultEnvironmentVariable+" or the XDG data directory")
+	rootCmd.PersistentFlags().StringVar(&rootP.profile, "profile", "", "Profile of the vault to use instead of the one selected with 'derivatex profile use'")
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "derivatex",
 	Short: "Derivatex is a smart pseudo-random password generator",
 	Long: `Derivatex is a smart pseudo-random password generator. More
 info can be found at https://github.com/techsek/derivatex`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// the selected profile may have been deleted, only the commands choosing a profile fall back to the default profile
+		fallbackToDefault := cmd == profileUseCmd || cmd == profileListCmd
+		fellBack, err := internal.SetVault(rootP.vault, rootP.profile, fallbackToDefault)
+		if err != nil {
+			exitWithVaultError(cmd, "Error opening the vault ("+err.Error()+")")
+		}
+		if fellBack {
+			color.HiYellow("The selected profile does not exist anymore, using the profile " + constants.DefaultProfileName + " instead")
+		}
+		err = internal.InitiateDatabaseIfNeeded()
+		if err != nil {
+			exitWithVaultError(cmd, "Error initiating database file '"+constants.DatabaseFilename+"' ("+err.Error()+")")
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
 }
 
+// exitWithVaultError prints the error opening the vault, as JSON for create --non-interactive, and exits
+func exitWithVaultError(cmd *cobra.Command, message string) {
+	if cmd == createCmd && createP.nonInteractive {
+		os.Exit(printCreateResult(createResult{Error: message}))
+	}
+	color.HiRed(message)
+	os.Exit(1)
+}
+
 // Execute is the cli entrypoint
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+)
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesShowCmd)
+	rulesCmd.AddCommand(rulesImportCmd)
+}
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage the known password rules of websites",
+	Long: `Manage the known password rules of websites, applied by 'derivatex generate' to new websites
+	when no generation flag is given. Rules are bundled with this program and can be updated by importing
+	the password rules file of https://github.com/apple/password-manager-resources.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var rulesShowCmd = &cobra.Command{
+	Use:   "show <website>",
+	Short: "Show the known password rules of a website",
+	Long:  `Show the known password rules of a website given as a domain or URL, or of one of its parent domains.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		siteRules, found, err := internal.FindSiteRules(args[0])
+		if err != nil {
+			color.HiRed("Error reading the site rules (" + err.Error() + ")")
+			return
+		}
+		if !found {
+			color.Yellow("No password rules are known for '" + internal.NormalizeDomain(args[0]) + "'")
+			return
+		}
+		fmt.Println(color.HiGreenString("Domain: ") + color.HiWhiteString(siteRules.Domain))
+		fmt.Println(color.HiGreenString("Source: ") + color.HiWhiteString(siteRules.Source))
+		fmt.Println(color.HiGreenString("Password rules: ") + color.HiWhiteString(siteRules.PasswordRules))
+	},
+}
+
+var rulesImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import password rules of websites from a JSON file",
+	Long: `Import password rules of websites from a JSON file in the format of the password-rules.json
+	file of https://github.com/apple/password-manager-resources, i.e. {"bank.com": {"password-rules": "minlength: 8;"}}.
+	Imported rules replace the bundled rules of the same domains for the current profile.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		content, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			color.HiRed("Error reading the site rules file (" + err.Error() + ")")
+			return
+		}
+		sitesRules, err := internal.ParseSiteRulesFile(content)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		err = internal.ImportSiteRules(sitesRules)
+		if err != nil {
+			color.HiRed("Error saving the site rules in the database (" + err.Error() + ")")
+			return
+		}
+		color.HiGreen("Password rules of " + strconv.Itoa(len(sitesRules)) + " websites imported")
+	},
+}
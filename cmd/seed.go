@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+)
+
+func init() {
+	rootCmd.AddCommand(seedCmd)
+}
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Back up, restore and derive child seeds of the seed",
+	Long:  `Back up the seed for offline storage and restore it, or derive child seeds for less trusted devices.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// readDecryptedSeed reads the seed file and decrypts its seed if needed
+func readDecryptedSeed() (seedFile *internal.SeedFileType, seed *[]byte, err error) {
+	seedFile, err = internal.ReadSeed()
+	if err != nil {
+		return nil, nil, err
+	}
+	seed = seedFile.Seed
+	if seedFile.IsProtected() {
+		seed = decryptSeedInteractively(seedFile)
+	}
+	return seedFile, seed, nil
+}
+
+// confirmSeedOverwrite asks for confirmation if the seed file already exists
+func confirmSeedOverwrite() bool {
+	exists, err := internal.SeedFileExists()
+	if err != nil {
+		color.HiRed("An error occurred checking the seed file: " + err.Error())
+		return false
+	}
+	if !exists {
+		return true
+	}
+	overwrite := internal.ReadInput("Your current seed file will be overwritten, do you want to continue? (yes/no) [no]: ")
+	return overwrite == "yes"
+}
+
+// decryptSeedInteractively asks for the factors required until the seed of the seed file is decrypted
+func decryptSeedInteractively(seedFile *internal.SeedFileType) (seed *[]byte) {
+	for {
+		var keyfileSHA3 *[32]byte
+		if seedFile.RequiresKeyfile() {
+			keyfileSHA3 = readKeyfile()
+		}
+		var passphraseBytesPtr *[]byte
+		if seedFile.RequiresPassphrase() {
+			var err error
+			passphraseBytesPtr, err = internal.ReadSecret("Enter your passphrase to decrypt the seed: ")
+			if err != nil {
+				color.Yellow("An error occurred reading the passphrase: " + err.Error())
+				internal.ClearByteArray32(keyfileSHA3)
+				continue
+			}
+		}
+		seed, err := internal.DecryptSeed(seedFile, passphraseBytesPtr, keyfileSHA3)
+		internal.ClearByteSlice(passphraseBytesPtr)
+		internal.ClearByteArray32(keyfileSHA3)
+		if err != nil {
+			internal.ClearByteSlice(seed)
+			color.HiRed("Seed, passphrase or keyfile is invalid: " + err.Error())
+			if seedFile.RequiresKeyfile() {
+				keyfilePath = "" // ask for the keyfile again
+			}
+			continue
+		}
+		return seed
+	}
+}
+
+// readKeyfile hashes the keyfile given with --keyfile, or asks for its path until it can be read
+func readKeyfile() (keyfileSHA3 *[32]byte) {
+	for {
+		for keyfilePath == "" {
+			keyfilePath = internal.ReadInput("Enter the path of your keyfile: ")
+		}
+		digest, err := internal.HashKeyfile(keyfilePath)
+		if err != nil {
+			color.Yellow("An error occurred reading the keyfile: " + err.Error())
+			keyfilePath = ""
+			continue
+		}
+		return digest
+	}
+}
+
+// protectSeedInteractively asks how to protect the decrypted seed and sets it accordingly in the seed file
+func protectSeedInteractively(seedFile *internal.SeedFileType, seed *[]byte) (err error) {
+	color.HiWhite("To generate a password, would you like to add one of the following securities:")
+	fmt.Println("A: Randomly picked adjective and noun passphrase " + color.HiGreenString("(recommended)"))
+	fmt.Println("B: Your own passphrase " + color.HiYellowString("(not recommended unless you know what you are doing)"))
+	fmt.Println("C: None " + color.HiRedString("(not recommended unless you seed.txt is safe at all time)"))
+	fmt.Println("D: Keyfile only, any file you keep apart i.e. on a USB stick " + color.HiYellowString("(not recommended unless the keyfile is backed up)"))
+	color.White(`For choices A, B and D, your seed and identifications database will be encrypted by AES
+using a 256 bit key generated by Argon2ID from the passphrase and or the keyfile. You will need them to do any further operation.
+For choices A and B, a keyfile can be required in addition to the passphrase.`)
+	for {
+		protectionOption := internal.ReadInput("Please enter an additional security option [A]: ")
+		if protectionOption == "A" || protectionOption == "" || protectionOption == "B" {
+			var passphrase string
+			if protectionOption == "A" || protectionOption == "" {
+				for {
+					passphrase, err = internal.MakePassphrase()
+					if err != nil {
+						color.Yellow("An error occurred when generating the passphrase: " + err.Error())
+						continue
+					}
+					fmt.Println("Your generated passphrase is: " + color.HiGreenString(passphrase))
+					anotherOne := internal.ReadInput("Would you prefer another passphrase? (yes/no) [yes]: ")
+					if anotherOne == "" || anotherOne == "yes" {
+						continue
+					} else if anotherOne == "no" {
+						break
+					}
+					color.Yellow("The answer '" + anotherOne + "' is not valid. Please try again.")
+				}
+			} else if protectionOption == "B" {
+				passphrase = internal.ReadInput("Enter your passphrase: ")
+			}
+			passphraseBytes := []byte(passphrase)
+			var keyfileSHA3 *[32]byte
+			addKeyfile := internal.ReadInput("Would you like to also require a keyfile? (yes/no) [no]: ")
+			if addKeyfile == "yes" {
+				keyfileSHA3 = readKeyfile()
+			}
+			seedFile.PassphraseArgon = internal.DefaultPassphraseArgonParams
+			err = internal.EncryptSeed(seedFile, seed, &passphraseBytes, keyfileSHA3)
+			internal.ClearByteArray32(keyfileSHA3)
+			if err != nil {
+				return err
+			}
+			if keyfileSHA3 != nil {
+				color.HiGreen("Seed encrypted using passphrase and keyfile successfully.")
+			} else {
+				color.HiGreen("Seed encrypted using passphrase successfully.")
+			}
+			return nil
+		} else if protectionOption == "C" {
+			internal.RemoveSeedProtection(seedFile, seed)
+			return nil
+		} else if protectionOption == "D" {
+			keyfileSHA3 := readKeyfile()
+			seedFile.PassphraseArgon = internal.DefaultPassphraseArgonParams
+			err = internal.EncryptSeed(seedFile, seed, nil, keyfileSHA3)
+			internal.ClearByteArray32(keyfileSHA3)
+			if err != nil {
+				return err
+			}
+			color.HiGreen("Seed encrypted using keyfile successfully.")
+			color.HiYellow("Keep a copy of your keyfile, its content must never change.")
+			return nil
+		}
+		color.Yellow("Option '" + protectionOption + "' is not valid. Please try again.")
+	}
+}
+
+// displayWords prints the numbered words, 6 per line
+func displayWords(words []string) {
+	for i := range words {
+		fmt.Printf("%3s %-9s", strconv.Itoa(i+1)+".", words[i])
+		if (i+1)%6 == 0 || i == len(words)-1 {
+			fmt.Println()
+		}
+	}
+}
+
+// readSeedToBackUp reads the seed file and decrypts its seed to back it up as words or shares, and refuses
+// a child seed whose namespace would be lost, printing the errors
+func readSeedToBackUp() (seedFile *internal.SeedFileType, seed *[]byte, ok bool) {
+	seedFile, err := internal.ReadSeed()
+	if err != nil {
+		color.Yellow("An error occurred reading the seed file: " + err.Error())
+		return nil, nil, false
+	}
+	if seedFile.IsChild() {
+		internal.ClearByteSlice(seedFile.Seed)
+		color.HiRed("A child seed only generates the passwords of its namespace " + seedFile.Namespace + ", please back up its seed file with 'derivatex seed backup' which keeps its namespace")
+		return nil, nil, false
+	}
+	seed = seedFile.Seed
+	if seedFile.IsProtected() {
+		seed = decryptSeedInteractively(seedFile)
+	}
+	return seedFile, seed, true
+}
+
+// seedWords encodes the seed as words ending with a checksum, and clears the seed
+func seedWords(seed *[]byte) (words []string, err error) {
+	words, err = internal.MakeMnemonic(seed)
+	internal.ClearByteSlice(seed)
+	if err != nil {
+		return nil, errors.New("An error occurred encoding the seed: " + err.Error())
+	}
+	return words, nil
+}
+
+// validSharesThreshold returns true if the seed can be split into the number of shares with the threshold
+func validSharesThreshold(shares, threshold int) bool {
+	return threshold >= 2 && threshold <= 255 && shares >= threshold && shares <= 255
+}
+
+// seedWordsType is the seed or one of its shares encoded as words, with a title such as Share 1/5 (threshold 3)
+type seedWordsType struct {
+	title string
+	words []string
+}
+
+// splitSeedWords splits the seed into shares encoded as words ending with a checksum, and clears the seed
+func splitSeedWords(seed *[]byte, shares, threshold int) (shareWords []seedWordsType, err error) {
+	splitShares, err := internal.SplitSecret(seed, uint8(shares), uint8(threshold), io.ReadFull)
+	internal.ClearByteSlice(seed)
+	if err != nil {
+		return nil, errors.New("An error occurred splitting the seed: " + err.Error())
+	}
+	defer func() {
+		for _, share := range splitShares {
+			internal.ClearByteSlice(share.Data)
+		}
+	}()
+	for _, share := range splitShares {
+		data := share.Serialize()
+		words, err := internal.MakeMnemonic(data)
+		internal.ClearByteSlice(data)
+		if err != nil {
+			return nil, errors.New("An error occurred encoding the share: " + err.Error())
+		}
+		title := "Share " + strconv.Itoa(int(share.Index)) + "/" + strconv.Itoa(shares) + " (threshold " + strconv.Itoa(threshold) + ")"
+		shareWords = append(shareWords, seedWordsType{title: title, words: words})
+	}
+	return shareWords, nil
+}
+
+// parseShareWords parses the words of a share and checks it can be combined with the shares already given
+func parseShareWords(words string, shares []internal.ShareType) (share internal.ShareType, err error) {
+	data, err := internal.ParseMnemonic(strings.Fields(words))
+	if err != nil {
+		return share, errors.New("The words of the share are not valid (" + err.Error() + ")")
+	}
+	share, err = internal.ParseShare(data)
+	internal.ClearByteSlice(data)
+	if err != nil {
+		return share, errors.New("The share is not valid (" + err.Error() + ")")
+	}
+	err = internal.ValidateShares(append(shares, share))
+	if err != nil {
+		internal.ClearByteSlice(share.Data)
+		return internal.ShareType{}, errors.New("The share can't be used (" + err.Error() + ")")
+	}
+	return share, nil
+}
+
+// saveRestoredSeed asks for the default user if needed and how to protect
+// the seed restored from a backup, and writes the seed file
+func saveRestoredSeed(defaultUser string, seed *[]byte) {
+	for defaultUser == "" {
+		defaultUser = internal.ReadInput("Enter your default user (i.e. email@domain.com): ")
+		if defaultUser == "" {
+			color.Yellow("Please enter a user and try again.")
+		}
+	}
+	seedFile := internal.NewSeedFile(defaultUser, seed)
+	seedFile.UseUnknownSalt()
+	err := protectSeedInteractively(seedFile, seed)
+	if err != nil {
+		color.HiRed("The following error occurred when encrypting the seed: " + err.Error())
+		return
+	}
+	err = internal.WriteSeed(seedFile)
+	internal.ClearByteSlice(seedFile.Seed)
+	if err != nil {
+		color.HiRed("Error writing seed to file: " + err.Error())
+		return
+	}
+	color.HiGreen("Seed restored successfully!")
+}
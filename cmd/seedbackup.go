@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/constants"
+	"github.com/techsek/derivatex/internal"
+)
+
+type seedBackupParams struct {
+	outputFilename string
+	mnemonic       bool
+	shares         int
+	threshold      int
+}
+
+var seedBackupP seedBackupParams
+
+func init() {
+	seedCmd.AddCommand(seedBackupCmd)
+
+	seedBackupCmd.Flags().StringVar(&seedBackupP.outputFilename, "output", "seed-backup.html", "File name to store the printable HTML page")
+	seedBackupCmd.Flags().BoolVar(&seedBackupP.mnemonic, "mnemonic", false, "Back up the decrypted seed as words instead of the encrypted seed file")
+	seedBackupCmd.Flags().IntVar(&seedBackupP.shares, "shares", 0, "Back up the decrypted seed split into this number of shares instead of the encrypted seed file")
+	seedBackupCmd.Flags().IntVar(&seedBackupP.threshold, "threshold", 3, "Number of shares needed to recreate the seed, used with --shares")
+}
+
+var seedBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create a printable page of QR codes to back up the seed",
+	Long: `Create a self-contained HTML page to print, with the seed as QR codes, their fingerprints and the seed file parameters.
+	By default, the seed file with its encrypted seed is backed up and can be restored with 'derivatex seed restore --file'.
+	With --mnemonic or --shares, the decrypted seed is backed up as words, or split into shares of words,
+	which can be restored with 'derivatex seed restore --file' or 'derivatex seed combine --file'
+	from the scanned QR codes, one share per line.
+	Scan the QR codes from an offline device only.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if seedBackupP.mnemonic && seedBackupP.shares > 0 {
+			color.HiRed("Please choose either --mnemonic or --shares")
+			return
+		}
+		if seedBackupP.shares > 0 && !validSharesThreshold(seedBackupP.shares, seedBackupP.threshold) {
+			color.HiRed("The threshold must be between 2 and the number of shares, which must be at most 255")
+			return
+		}
+		var seedFile *internal.SeedFileType
+		var pieces []internal.BackupPieceType
+		if !seedBackupP.mnemonic && seedBackupP.shares == 0 {
+			var err error
+			seedFile, err = internal.ReadSeed()
+			if err != nil {
+				color.Yellow("An error occurred reading the seed file: " + err.Error())
+				return
+			}
+			if !seedFile.IsProtected() {
+				color.HiYellow("Your seed is not protected by a passphrase, the QR code will contain it unencrypted.")
+			}
+			piece, err := internal.NewBackupPiece(constants.SeedFilename, seedFile.BackupPayload())
+			if err != nil {
+				color.HiRed("An error occurred creating the QR code: " + err.Error())
+				return
+			}
+			pieces = append(pieces, piece)
+		} else {
+			var seed *[]byte
+			var ok bool
+			seedFile, seed, ok = readSeedToBackUp()
+			if !ok {
+				return
+			}
+			var err error
+			var shareWords []seedWordsType
+			if seedBackupP.mnemonic {
+				var words []string
+				words, err = seedWords(seed)
+				shareWords = []seedWordsType{{title: "Seed words", words: words}}
+			} else {
+				shareWords, err = splitSeedWords(seed, seedBackupP.shares, seedBackupP.threshold)
+			}
+			if err != nil {
+				color.HiRed(err.Error())
+				return
+			}
+			for _, share := range shareWords {
+				piece, err := internal.NewBackupPiece(share.title, strings.Join(share.words, " "))
+				if err != nil {
+					color.HiRed("An error occurred creating the QR code: " + err.Error())
+					return
+				}
+				pieces = append(pieces, piece)
+			}
+		}
+		parameters := append(seedFile.BackupParameters(), "Backup created on: "+time.Now().Format("02/01/2006"))
+		html, err := internal.MakeBackupHTML("Derivatex seed backup", parameters, pieces)
+		if err != nil {
+			color.HiRed("An error occurred creating the backup page: " + err.Error())
+			return
+		}
+		err = ioutil.WriteFile(seedBackupP.outputFilename, *html, 0600)
+		internal.ClearByteSlice(html)
+		if err != nil {
+			color.HiRed("Error writing the backup page to file: " + err.Error())
+			return
+		}
+		color.HiGreen("Backup page written to " + seedBackupP.outputFilename + ", print it and delete the file.")
+	},
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+)
+
+type seedCombineParams struct {
+	defaultUser string
+	filenames   []string
+}
+
+var seedCombineP seedCombineParams
+
+func init() {
+	seedCmd.AddCommand(seedCombineCmd)
+
+	seedCombineCmd.Flags().StringVar(&seedCombineP.defaultUser, "user", "", "Your default user to be used when generating passwords without specifying a particular user")
+	seedCombineCmd.Flags().StringArrayVar(&seedCombineP.filenames, "file", nil, "File containing the words of shares, such as the scanned QR codes of 'derivatex seed backup --shares', one share per line, can be repeated")
+}
+
+var seedCombineCmd = &cobra.Command{
+	Use:   "combine",
+	Short: "Restore the seed file from a threshold of shares",
+	Long: `Restore the seed.txt file from a threshold of the shares given by 'derivatex seed split' or 'derivatex seed backup --shares'.
+	With --file, the shares are read from files of one share per line, such as the scanned QR codes of the backup page,
+	and the missing shares are then entered interactively.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !confirmSeedOverwrite() {
+			return
+		}
+		var shares []internal.ShareType
+		defer func() {
+			for _, share := range shares {
+				internal.ClearByteSlice(share.Data)
+			}
+		}()
+		for _, filename := range seedCombineP.filenames {
+			var ok bool
+			shares, ok = readShareFile(filename, shares)
+			if !ok {
+				return
+			}
+		}
+		for len(shares) == 0 || len(shares) < int(shares[0].Threshold) {
+			words := internal.ReadInput("Enter the words of share " + strconv.Itoa(len(shares)+1) + " separated by spaces: ")
+			share, err := parseShareWords(words, shares)
+			if err != nil {
+				color.Yellow(err.Error() + ", please try again.")
+				continue
+			}
+			shares = append(shares, share)
+			color.HiGreen("Share " + strconv.Itoa(int(share.Index)) + " accepted, " + strconv.Itoa(len(shares)) + "/" + strconv.Itoa(int(share.Threshold)) + " shares entered.")
+		}
+		seed, err := internal.CombineShares(shares)
+		if err != nil {
+			color.HiRed("An error occurred combining the shares: " + err.Error())
+			return
+		}
+		saveRestoredSeed(seedCombineP.defaultUser, seed)
+	},
+}
+
+// readShareFile adds the shares of the file, one per line, to the shares already given
+func readShareFile(filename string, shares []internal.ShareType) (allShares []internal.ShareType, ok bool) {
+	file, err := os.Open(filename)
+	if err != nil {
+		color.HiRed("Error reading the shares file: " + err.Error())
+		return shares, false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		share, err := parseShareWords(scanner.Text(), shares)
+		if err != nil {
+			color.HiRed(err.Error() + " on line " + strconv.Itoa(line) + " of " + filename)
+			return shares, false
+		}
+		shares = append(shares, share)
+		color.HiGreen("Share " + strconv.Itoa(int(share.Index)) + " accepted from " + filename + ", " + strconv.Itoa(len(shares)) + "/" + strconv.Itoa(int(share.Threshold)) + " shares entered.")
+	}
+	err = scanner.Err()
+	if err != nil {
+		color.HiRed("Error reading the shares file: " + err.Error())
+		return shares, false
+	}
+	return shares, true
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/constants"
+	"github.com/techsek/derivatex/internal"
+)
+
+type seedDeriveChildParams struct {
+	output      string
+	defaultUser string
+	force       bool
+}
+
+var seedDeriveChildP seedDeriveChildParams
+
+func init() {
+	seedCmd.AddCommand(seedDeriveChildCmd)
+
+	seedDeriveChildCmd.Flags().StringVar(&seedDeriveChildP.output, "output", "", "File of the child seed, to copy as "+constants.SeedFilename+" in the vault of the other device, defaults to seed_<namespace>.txt")
+	seedDeriveChildCmd.Flags().StringVar(&seedDeriveChildP.defaultUser, "user", "", "Default user of the child seed, defaults to your default user")
+	seedDeriveChildCmd.Flags().BoolVar(&seedDeriveChildP.force, "force", false, "Overwrite an existing child seed file")
+}
+
+var seedDeriveChildCmd = &cobra.Command{
+	Use:   "derive-child <namespace>",
+	Short: "Derive a child seed generating only the passwords of a namespace",
+	Long: `Derive a child seed file which only generates the passwords of the websites of a namespace,
+	such as work/github.com for the namespace work (or work/*), to use on a less trusted device.
+	The child seed can't be computed back to your seed. The namespace is only written in the header of the child seed file,
+	and your seed generates the passwords of the websites of any namespace from its child seed with the password
+	derivation version 5 or above, so both seeds generate the same passwords without anything saved in the database.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, err := internal.ParseNamespace(args[0])
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		output := seedDeriveChildP.output
+		if output == "" {
+			output = keyFilename("seed_", namespace) + ".txt"
+		}
+		if _, err := os.Stat(output); err == nil && !seedDeriveChildP.force {
+			color.HiRed("The file " + output + " already exists, use --force to overwrite it")
+			return
+		}
+		count, err := internal.CountNamespaceIdentifications(namespace)
+		if err != nil {
+			color.HiRed("Error reading the database (" + err.Error() + ")")
+			return
+		}
+		if count > 0 {
+			color.HiYellow(strconv.Itoa(count) + " identifications of websites starting with " + namespace + internal.NamespaceSeparator +
+				" use a password derivation version before " + strconv.Itoa(internal.NamespaceDerivationVersion) +
+				", the child seed can't generate their passwords until you change them to the latest version.")
+		}
+
+		seedFile, seed, err := readDecryptedSeed()
+		if err != nil {
+			color.Yellow("An error occurred reading the seed file: " + err.Error())
+			return
+		}
+		if seedFile.IsChild() {
+			internal.ClearByteSlice(seed)
+			color.HiRed("This seed is already the child seed of the namespace " + seedFile.Namespace + ", it can't derive other child seeds")
+			return
+		}
+		childSeed, err := internal.DeriveChildSeed(seed, namespace, internal.ChildSeedDerivationVersion)
+		internal.ClearByteSlice(seed)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		defer internal.ClearByteSlice(childSeed)
+		childFile := internal.NewChildSeedFile(seedFile, namespace, childSeed)
+		if seedDeriveChildP.defaultUser != "" {
+			childFile.DefaultUser = seedDeriveChildP.defaultUser
+		}
+		color.HiWhite("The child seed file is protected separately from your seed file.")
+		err = protectSeedInteractively(childFile, childSeed)
+		if err != nil {
+			color.HiRed("The following error occurred when encrypting the child seed: " + err.Error())
+			return
+		}
+		err = internal.WriteSeedTo(childFile, output, 0600)
+		if err != nil {
+			color.HiRed("Error writing the child seed to file: " + err.Error())
+			return
+		}
+		fmt.Println(color.HiGreenString("Child seed: ") + color.HiWhiteString(output))
+		fmt.Println(color.HiGreenString("Namespace: ") + color.HiWhiteString(namespace+internal.NamespaceSeparator+"*"))
+		color.HiWhite("Copy it as " + constants.SeedFilename + " in the vault of the other device, and generate the passwords of the namespace with the same settings, and the password derivation version " + strconv.Itoa(internal.NamespaceDerivationVersion) + " or above, on both devices.")
+	},
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type seedExportParams struct {
+	mnemonic bool
+}
+
+var seedExportP seedExportParams
+
+func init() {
+	seedCmd.AddCommand(seedExportCmd)
+
+	seedExportCmd.Flags().BoolVar(&seedExportP.mnemonic, "mnemonic", false, "Export the seed as a list of words with a checksum")
+}
+
+var seedExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the seed for an offline paper backup",
+	Long: `Export the decrypted seed for an offline paper backup, which can be restored with 'derivatex seed restore'.
+	Anyone with the exported seed can generate all your passwords.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !seedExportP.mnemonic {
+			color.Yellow("Please specify an export format such as --mnemonic")
+			return
+		}
+		_, seed, ok := readSeedToBackUp()
+		if !ok {
+			return
+		}
+		words, err := seedWords(seed)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		color.HiYellow("Anyone with these words can generate all your passwords, write them down and keep them offline.")
+		displayWords(words)
+	},
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+)
+
+type seedRestoreParams struct {
+	defaultUser string
+	filename    string
+}
+
+var seedRestoreP seedRestoreParams
+
+func init() {
+	seedCmd.AddCommand(seedRestoreCmd)
+
+	seedRestoreCmd.Flags().StringVar(&seedRestoreP.defaultUser, "user", "", "Your default user to be used when generating passwords without specifying a particular user")
+	seedRestoreCmd.Flags().StringVar(&seedRestoreP.filename, "file", "", "File containing the scanned QR code of a backup, either the seed file or the words")
+}
+
+var seedRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the seed file from a mnemonic or QR code backup",
+	Long: `Restore the seed.txt file from the words given by 'derivatex seed export --mnemonic',
+	without computing the seed again from your master password and birthdate.
+	With --file, restore from the scanned content of a QR code of 'derivatex seed backup'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !confirmSeedOverwrite() {
+			return
+		}
+		var seed *[]byte
+		if seedRestoreP.filename != "" {
+			content := new([]byte)
+			var err error
+			*content, err = ioutil.ReadFile(seedRestoreP.filename)
+			if err != nil {
+				color.HiRed("Error reading the backup file: " + err.Error())
+				return
+			}
+			if internal.IsSeedFileContent(*content) {
+				seedFile, err := internal.ParseSeedFile(content)
+				if err != nil {
+					color.HiRed("The backup is not a valid seed file: " + err.Error())
+					return
+				}
+				err = internal.WriteSeed(seedFile)
+				internal.ClearByteSlice(seedFile.Seed)
+				if err != nil {
+					color.HiRed("Error writing seed to file: " + err.Error())
+					return
+				}
+				color.HiGreen("Seed restored successfully!")
+				return
+			}
+			seed, err = internal.ParseMnemonic(strings.Fields(string(*content)))
+			internal.ClearByteSlice(content)
+			if err != nil {
+				color.HiRed("The words of the backup are not valid: " + err.Error())
+				return
+			}
+			saveRestoredSeed(seedRestoreP.defaultUser, seed)
+			return
+		}
+		for {
+			mnemonic := internal.ReadInput("Enter your words separated by spaces: ")
+			var err error
+			seed, err = internal.ParseMnemonic(strings.Fields(mnemonic))
+			if err != nil {
+				color.Yellow("The words entered are not valid (" + err.Error() + "), please try again.")
+				continue
+			}
+			break
+		}
+		saveRestoredSeed(seedRestoreP.defaultUser, seed)
+	},
+}
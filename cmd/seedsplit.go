@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type seedSplitParams struct {
+	shares    int
+	threshold int
+}
+
+var seedSplitP seedSplitParams
+
+func init() {
+	seedCmd.AddCommand(seedSplitCmd)
+
+	seedSplitCmd.Flags().IntVar(&seedSplitP.shares, "shares", 5, "Number of shares to split the seed into")
+	seedSplitCmd.Flags().IntVar(&seedSplitP.threshold, "threshold", 3, "Number of shares needed to recreate the seed")
+}
+
+var seedSplitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split the seed into shares, a threshold of which recreates it",
+	Long: `Split the decrypted seed into shares using Shamir secret sharing, so that any
+	threshold of shares recreates the seed with 'derivatex seed combine' and less shares reveal nothing about it.
+	Each share is displayed as a list of words ending with a checksum.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !validSharesThreshold(seedSplitP.shares, seedSplitP.threshold) {
+			color.HiRed("The threshold must be between 2 and the number of shares, which must be at most 255")
+			return
+		}
+		_, seed, ok := readSeedToBackUp()
+		if !ok {
+			return
+		}
+		shareWords, err := splitSeedWords(seed, seedSplitP.shares, seedSplitP.threshold)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		color.HiYellow("Give each share to a different custodian, " + strconv.Itoa(seedSplitP.threshold) + " of them can generate all your passwords.")
+		for _, share := range shareWords {
+			fmt.Println()
+			color.HiGreen(share.title + ":")
+			displayWords(share.words)
+		}
+	},
+}
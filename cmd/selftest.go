@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/constants"
+	"github.com/techsek/derivatex/internal"
+)
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+}
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Verify this program derives the same passwords as the frozen test vectors",
+	Long: `Derive the passwords of the known answer test vectors built into this program, for every
+	password derivation version, and verify they are identical to the frozen expected passwords.
+	Run it before trusting a new binary with your seed. It does not use your seed nor your vault.`,
+	Args:             cobra.NoArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {}, // no vault needed
+	Run: func(cmd *cobra.Command, args []string) {
+		counts, err := internal.CheckPasswordVectors()
+		if err != nil {
+			color.HiRed("Self test failed, do not use this program to generate your passwords: " + err.Error())
+			os.Exit(1)
+		}
+		for version := uint16(1); version <= constants.PasswordDerivationVersion; version++ {
+			color.HiGreen("Password derivation version " + strconv.FormatUint(uint64(version), 10) + ": " + strconv.Itoa(counts[version]) + " vectors passed")
+		}
+		color.HiGreen("Self test passed with vectors version " + strconv.Itoa(internal.PasswordVectorsVersion))
+	},
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/internal"
+	"golang.org/x/crypto/ed25519"
+)
+
+type sshKeyParams struct {
+	output         string
+	comment        string
+	passphrase     bool
+	passphraseFrom string
+	force          bool
+}
+
+var sshKeyP sshKeyParams
+
+func init() {
+	rootCmd.AddCommand(sshKeyCmd)
+
+	sshKeyCmd.Flags().StringVar(&sshKeyP.output, "output", "", "File of the private key, the public key being written to the same file with .pub appended, defaults to id_ed25519_<label>")
+	sshKeyCmd.Flags().StringVar(&sshKeyP.comment, "comment", "", "Comment of the key, defaults to the label")
+	sshKeyCmd.Flags().BoolVar(&sshKeyP.passphrase, "passphrase", false, "Encrypt the private key with a passphrase entered interactively")
+	sshKeyCmd.Flags().StringVar(&sshKeyP.passphraseFrom, "passphrase-from", "", "Encrypt the private key with the passphrase read from env:NAME, file:PATH or fd:N")
+	sshKeyCmd.Flags().BoolVar(&sshKeyP.force, "force", false, "Overwrite existing key files")
+}
+
+// keyFilename returns a file name of the label, replacing the characters other than letters, digits, dots and dashes
+func keyFilename(prefix, label string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, label)
+}
+
+// readKeyPassphrase reads the passphrase encrypting a derived private key from the source, or interactively
+// twice if interactive is true, and returns nil if no passphrase is requested
+func readKeyPassphrase(interactive bool, source string) (passphrase *[]byte, err error) {
+	if source != "" {
+		return internal.ReadSecretFrom(source)
+	}
+	if !interactive {
+		return nil, nil
+	}
+	for {
+		passphrase, err = internal.ReadSecret("Enter the passphrase of the private key: ")
+		if err != nil {
+			return nil, err
+		}
+		confirmation, err := internal.ReadSecret("Enter the passphrase again: ")
+		if err != nil {
+			internal.ClearByteSlice(passphrase)
+			return nil, err
+		}
+		equal := bytes.Equal(*passphrase, *confirmation)
+		internal.ClearByteSlice(confirmation)
+		if equal && len(*passphrase) > 0 {
+			return passphrase, nil
+		}
+		internal.ClearByteSlice(passphrase)
+		color.Yellow("The passphrases are empty or different. Please try again.")
+	}
+}
+
+// writeKeyFiles writes the files of a derived key, refusing to overwrite existing files unless force is true
+func writeKeyFiles(force bool, files map[string][]byte, modes map[string]os.FileMode) (ok bool) {
+	if !force {
+		for filename := range files {
+			if _, err := os.Stat(filename); err == nil {
+				color.HiRed("The file " + filename + " already exists, use --force to overwrite it")
+				return false
+			}
+		}
+	}
+	for filename, content := range files {
+		err := ioutil.WriteFile(filename, content, modes[filename])
+		if err != nil {
+			color.HiRed("Error writing the file " + filename + ": " + err.Error())
+			return false
+		}
+	}
+	return true
+}
+
+var sshKeyCmd = &cobra.Command{
+	Use:   "ssh-key <label>",
+	Short: "Derive a SSH key from the seed",
+	Long: `Derive an Ed25519 SSH key of a label from the seed and write its private and public keys
+	in the OpenSSH format. The label and the derivation version are saved in the database so that
+	the same key can be derived again on any machine with the seed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		label := args[0]
+		key, found, err := internal.FindKeyIdentification(internal.KeyTypeSSH, label)
+		if err != nil {
+			color.HiRed("Error reading the database (" + err.Error() + ")")
+			return
+		}
+		if !found {
+			key = internal.KeyIdentificationType{
+				Type:              internal.KeyTypeSSH,
+				Label:             label,
+				DerivationVersion: internal.SSHKeyDerivationVersion,
+				CreationTime:      time.Now().Unix(),
+				UserID:            label,
+			}
+		} else if key.DerivationVersion != internal.SSHKeyDerivationVersion {
+			color.HiYellow("This key is derived using the SSH key derivation version " + strconv.FormatUint(uint64(key.DerivationVersion), 10) + ", the latest version being " + strconv.Itoa(internal.SSHKeyDerivationVersion))
+		}
+		if cmd.Flags().Changed("comment") {
+			key.UserID = sshKeyP.comment
+		}
+		output := sshKeyP.output
+		if output == "" {
+			output = keyFilename("id_ed25519_", label)
+		}
+
+		passphrase, err := readKeyPassphrase(sshKeyP.passphrase, sshKeyP.passphraseFrom)
+		if err != nil {
+			color.HiRed("An error occurred reading the passphrase: " + err.Error())
+			return
+		}
+		defer internal.ClearByteSlice(passphrase)
+		seedFile, seed, err := readDecryptedSeed()
+		if err != nil {
+			color.Yellow("An error occurred reading the seed file: " + err.Error())
+			return
+		}
+		if seedFile.IsChild() {
+			internal.ClearByteSlice(seed)
+			color.HiRed("A child seed only generates the passwords of its namespace " + seedFile.Namespace + ", please derive keys with its parent seed")
+			return
+		}
+		privateKey, err := internal.DeriveSSHKey(seed, key.Label, key.DerivationVersion)
+		internal.ClearByteSlice(seed)
+		if err != nil {
+			color.HiRed(err.Error())
+			return
+		}
+		defer internal.ClearByteSlice((*[]byte)(&privateKey))
+		privatePEM, err := internal.MarshalSSHPrivateKey(privateKey, key.UserID, passphrase, io.ReadFull)
+		if err != nil {
+			color.HiRed("Error encoding the private key: " + err.Error())
+			return
+		}
+		publicKey, fingerprint, err := internal.MarshalSSHPublicKey(privateKey.Public().(ed25519.PublicKey), key.UserID)
+		if err != nil {
+			color.HiRed("Error encoding the public key: " + err.Error())
+			return
+		}
+		files := map[string][]byte{output: privatePEM, output + ".pub": publicKey}
+		if !writeKeyFiles(sshKeyP.force, files, map[string]os.FileMode{output: 0600, output + ".pub": 0644}) {
+			return
+		}
+		err = internal.InsertKeyIdentification(key)
+		if err != nil {
+			color.HiRed("Error saving the key in the database: " + err.Error())
+			return
+		}
+		if !found {
+			color.HiGreen("New SSH key label saved in database.")
+		}
+		fmt.Println(color.HiGreenString("Private key: ") + color.HiWhiteString(output))
+		fmt.Println(color.HiGreenString("Public key: ") + color.HiWhiteString(output+".pub"))
+		fmt.Println(color.HiGreenString("Fingerprint: ") + color.HiWhiteString(fingerprint))
+		fmt.Print(string(publicKey))
+	},
+}
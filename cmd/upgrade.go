@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/techsek/derivatex/constants"
+	"github.com/techsek/derivatex/internal"
+)
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade the seed file to the latest format",
+	Long: `Upgrade the seed.txt file to the latest format.
+	A seed encrypted with the legacy AES-CFB cipher is decrypted with your passphrase and
+	encrypted again with AES-256-GCM using the same passphrase and keyfile.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		seedFile, err := internal.ReadSeed()
+		if err != nil {
+			color.Yellow("An error occurred reading the seed file: " + err.Error())
+			return
+		}
+		if !seedFile.NeedsUpgrade() {
+			color.HiGreen("Your " + constants.SeedFilename + " is already up to date.")
+			return
+		}
+		for {
+			var keyfileSHA3 *[32]byte
+			if seedFile.RequiresKeyfile() {
+				keyfileSHA3 = readKeyfile()
+			}
+			var passphraseBytesPtr *[]byte
+			if seedFile.RequiresPassphrase() {
+				passphraseBytesPtr, err = internal.ReadSecret("Enter your passphrase to decrypt the seed: ")
+				if err != nil {
+					color.Yellow("An error occurred reading the passphrase: " + err.Error())
+					internal.ClearByteArray32(keyfileSHA3)
+					continue
+				}
+			}
+			err = internal.UpgradeSeed(seedFile, passphraseBytesPtr, keyfileSHA3)
+			internal.ClearByteSlice(passphraseBytesPtr)
+			internal.ClearByteArray32(keyfileSHA3)
+			if err != nil {
+				color.HiRed("Seed, passphrase or keyfile is invalid: " + err.Error())
+				if seedFile.RequiresKeyfile() {
+					keyfilePath = "" // ask for the keyfile again
+				}
+				continue
+			}
+			break
+		}
+		err = internal.WriteSeed(seedFile)
+		internal.ClearByteSlice(seedFile.Seed)
+		if err != nil {
+			color.HiRed("Error writing seed to file: " + err.Error())
+			return
+		}
+		color.HiGreen("Seed upgraded successfully!")
+	},
+}
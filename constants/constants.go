@@ -9,18 +9,28 @@ const ArgonDigestSize uint32 = 64
 const ArgonTimeCost uint32 = 400
 const ArgonParallelism uint8 = 4
 const ArgonTestRounds uint32 = 40
+const SeedSaltSize = 16         // random salt used instead of the birthdate
+const RecoveryCodeGroupSize = 4 // characters per group of the recovery code of the random salt
 
 // Argon2ID settings for the optional passphrase to encrypt the seed
 const PassphraseArgonMemoryMB uint32 = 100
 const PassphraseArgonTimeCost uint32 = 10
 const PassphraseArgonParallelism uint8 = 4
+const PassphraseSaltSize = 16
 
 const SeedFilename = "seed.txt"
+const SeedFileVersion = 2
 const DefaultPasswordLength = 20
+const DefaultPinLength = 6
+const MaxPasswordLength = 4096
+const MinimumPasswordEntropyBits = 64 // below which generate warns
+const DefaultPronounceableLength = 32 // about the entropy of a password of DefaultPasswordLength characters
 const DatabaseFilename = "database.sqlite"
 const DefaultTableToDump = "identifications"
+const VaultEnvironmentVariable = "DERIVATEX_HOME"
+const DefaultProfileName = "default"
 
-const PasswordDerivationVersion = 3
+const PasswordDerivationVersion = 5
 
 const (
 	Symbols    = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
@@ -19,4 +19,5 @@ require (
 	golang.org/x/crypto v0.0.0-20181030102418-4d3f4d9ffa16
 	golang.org/x/sys v0.0.0-20181106073832-7155702f2d47 // indirect
 	gopkg.in/cheggaaa/pb.v1 v1.0.26
+	rsc.io/qr v0.2.0
 )
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/hex"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+	"rsc.io/qr"
+)
+
+// BackupPieceType is one QR code of a paper backup
+type BackupPieceType struct {
+	Title       string
+	Payload     string
+	Fingerprint string
+	QRCode      template.HTML
+}
+
+// NewBackupPiece renders the payload as a QR code with its fingerprint
+func NewBackupPiece(title string, payload string) (piece BackupPieceType, err error) {
+	svg, err := MakeQRCodeSVG(payload)
+	if err != nil {
+		return piece, err
+	}
+	return BackupPieceType{
+		Title:       title,
+		Payload:     payload,
+		Fingerprint: Fingerprint([]byte(payload)),
+		QRCode:      template.HTML(svg),
+	}, nil
+}
+
+// Fingerprint returns the first 8 bytes of the SHA3-256 digest of the data, in groups of hexadecimal characters
+func Fingerprint(data []byte) string {
+	digest := sha3.Sum256(data)
+	encoded := hex.EncodeToString(digest[:8])
+	var groups []string
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.ToUpper(strings.Join(groups, " "))
+}
+
+// MakeQRCodeSVG returns the payload encoded as a QR code in a standalone SVG element
+func MakeQRCodeSVG(payload string) (svg string, err error) {
+	code, err := qr.Encode(payload, qr.M)
+	if err != nil {
+		return "", err
+	}
+	const quietZone = 4
+	size := strconv.Itoa(code.Size + 2*quietZone)
+	var path bytes.Buffer
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Black(x, y) {
+				path.WriteString("M" + strconv.Itoa(x+quietZone) + " " + strconv.Itoa(y+quietZone) + "h1v1h-1z")
+			}
+		}
+	}
+	return `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 ` + size + ` ` + size + `" width="280" height="280" shape-rendering="crispEdges">` +
+		`<rect width="` + size + `" height="` + size + `" fill="#fff"/>` +
+		`<path fill="#000" d="` + path.String() + `"/></svg>`, nil
+}
+
+var backupTemplate = template.Must(template.New("backup").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: monospace; margin: 2em; }
+.piece { page-break-inside: avoid; border: 1px solid #000; padding: 1em; margin-bottom: 2em; }
+.payload { white-space: pre-wrap; word-break: break-all; font-size: 0.8em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Anyone with this page may be able to generate your passwords. Print it from an offline computer and keep it safe.</p>
+<ul>
+{{range .Parameters}}<li>{{.}}</li>
+{{end}}</ul>
+{{range .Pieces}}<div class="piece">
+<h2>{{.Title}}</h2>
+{{.QRCode}}
+<p>Fingerprint: {{.Fingerprint}}</p>
+<p class="payload">{{.Payload}}</p>
+</div>
+{{end}}</body>
+</html>
+`))
+
+// MakeBackupHTML returns a self-contained printable HTML page with each piece as a QR code
+func MakeBackupHTML(title string, parameters []string, pieces []BackupPieceType) (html *[]byte, err error) {
+	var buffer bytes.Buffer
+	err = backupTemplate.Execute(&buffer, struct {
+		Title      string
+		Parameters []string
+		Pieces     []BackupPieceType
+	}{title, parameters, pieces})
+	if err != nil {
+		return nil, err
+	}
+	html = new([]byte)
+	*html = buffer.Bytes()
+	return html, nil
+}
+
+// BackupParameters returns the non secret parameters of the seed file to print on a backup
+func (seedFile *SeedFileType) BackupParameters() []string {
+	return strings.Split(strings.TrimSuffix(string(seedFile.header()), "\n"), "\n")
+}
+
+// ParseSeedFile reads a seed file content, i.e. the payload of a backup QR code
+func ParseSeedFile(content *[]byte) (seedFile *SeedFileType, err error) {
+	return parseSeed(content)
+}
+
+// IsSeedFileContent returns true if the content is a seed file instead of i.e. mnemonic words
+func IsSeedFileContent(content []byte) bool {
+	content = bytes.TrimSpace(content)
+	return bytes.HasPrefix(content, []byte(seedVersionPrefix)) || bytes.HasPrefix(content, []byte("Default user: "))
+}
+
+// BackupPayload returns the whole seed file content, with the seed encrypted if it is protected
+func (seedFile *SeedFileType) BackupPayload() string {
+	content := seedFile.serialize()
+	payload := string(*content)
+	ClearByteSlice(content)
+	return payload
+}
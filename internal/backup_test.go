@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	out := Fingerprint([]byte("derivatex"))
+	if len(out) != 19 || strings.Count(out, " ") != 3 || out != strings.ToUpper(out) {
+		t.Errorf("Fingerprint() == %s is not 4 groups of 4 uppercase hexadecimal characters", out)
+	}
+	if Fingerprint([]byte("derivatex")) != out {
+		t.Errorf("Fingerprint() is not deterministic")
+	}
+	if Fingerprint([]byte("derivatey")) == out {
+		t.Errorf("Fingerprint() is the same for different data")
+	}
+}
+
+func TestIsSeedFileContent(t *testing.T) {
+	seed := []byte{1, 2, 3}
+	cases := []struct {
+		content []byte
+		isSeed  bool
+	}{
+		{*NewSeedFile("a@a.com", &seed).serialize(), true},
+		{[]byte("Default user: a@a.com\nProtection: none\nSecret Seed: AQID"), true},
+		{[]byte("\nDerivatex seed file version: 2\n"), true},
+		{[]byte("abandon ability able"), false},
+		{[]byte{}, false},
+	}
+	for _, c := range cases {
+		out := IsSeedFileContent(c.content)
+		if out != c.isSeed {
+			t.Errorf("IsSeedFileContent(%q) == %t want %t", c.content, out, c.isSeed)
+		}
+	}
+}
+
+func TestMakeBackupHTML(t *testing.T) {
+	seed := []byte{1, 2, 3}
+	seedFile := NewSeedFile("<script>@a.com", &seed)
+	piece, err := NewBackupPiece("Seed file", seedFile.BackupPayload())
+	if err != nil {
+		t.Fatalf("NewBackupPiece() - %s", err)
+	}
+	if !strings.HasPrefix(string(piece.QRCode), "<svg ") {
+		t.Errorf("NewBackupPiece() QR code is not an SVG element: %s", piece.QRCode)
+	}
+	html, err := MakeBackupHTML("Backup", seedFile.BackupParameters(), []BackupPieceType{piece})
+	if err != nil {
+		t.Fatalf("MakeBackupHTML() - %s", err)
+	}
+	if bytes.Contains(*html, []byte("<script>")) {
+		t.Errorf("MakeBackupHTML() does not escape the seed file content")
+	}
+	for _, expected := range []string{piece.Fingerprint, "Protection: none", string(piece.QRCode)} {
+		if !bytes.Contains(*html, []byte(expected)) {
+			t.Errorf("MakeBackupHTML() does not contain %q", expected)
+		}
+	}
+	payload := []byte(piece.Payload)
+	parsed, err := ParseSeedFile(&payload)
+	if err != nil {
+		t.Fatalf("ParseSeedFile() - %s", err)
+	}
+	if parsed.DefaultUser != seedFile.DefaultUser || !bytes.Equal(*parsed.Seed, seed) {
+		t.Errorf("ParseSeedFile() of the backup payload == %v want %v", parsed, seedFile)
+	}
+}
+
+func TestChildSeedBackupPayload(t *testing.T) {
+	seed := []byte{1, 2, 3, 4}
+	childSeed, _ := DeriveChildSeed(&seed, "work", ChildSeedDerivationVersion)
+	childFile := NewChildSeedFile(NewSeedFile("a@a", &seed), "work", childSeed)
+	content := []byte(childFile.BackupPayload())
+	out, err := ParseSeedFile(&content)
+	if err != nil {
+		t.Fatalf("ParseSeedFile(BackupPayload()) - %s", err)
+	}
+	if out.Namespace != "work" {
+		t.Errorf("ParseSeedFile(BackupPayload()) namespace == %q want work", out.Namespace)
+	}
+	websiteSeed, err := out.WebsiteSeed(out.Seed, "work/github.com", NamespaceDerivationVersion)
+	if err != nil || !bytes.Equal(*websiteSeed, *childSeed) {
+		t.Errorf("WebsiteSeed() of the restored child seed == %x, %v want the child seed", *websiteSeed, err)
+	}
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"crypto/sha512"
+	"errors"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+// bcryptPBKDFMagic is the plaintext encrypted by bcryptHash, as in OpenBSD
+var bcryptPBKDFMagic = []byte("OxychromaticBlowfishSwatDynamite")
+
+const bcryptPBKDFBlockSize = 32
+
+// bcryptPBKDF derives a key from the password and salt as the bcrypt_pbkdf function of OpenBSD,
+// used by OpenSSH to encrypt private keys with a passphrase
+func bcryptPBKDF(password, salt []byte, rounds, keyLength int) (key []byte, err error) {
+	if rounds < 1 {
+		return nil, errors.New("bcrypt_pbkdf requires at least one round")
+	}
+	if len(password) == 0 || len(salt) == 0 || keyLength < 1 || keyLength > bcryptPBKDFBlockSize*bcryptPBKDFBlockSize {
+		return nil, errors.New("bcrypt_pbkdf requires a password, a salt and a key length between 1 and 1024")
+	}
+	blocks := (keyLength + bcryptPBKDFBlockSize - 1) / bcryptPBKDFBlockSize
+	key = make([]byte, blocks*bcryptPBKDFBlockSize)
+	h := sha512.New()
+	h.Write(password)
+	passwordSHA512 := h.Sum(nil)
+	tmp := make([]byte, bcryptPBKDFBlockSize)
+	for block := 1; block <= blocks; block++ {
+		h.Reset()
+		h.Write(salt)
+		h.Write([]byte{byte(block >> 24), byte(block >> 16), byte(block >> 8), byte(block)})
+		err = bcryptHash(tmp, passwordSHA512, h.Sum(nil))
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, bcryptPBKDFBlockSize)
+		copy(out, tmp)
+		for i := 2; i <= rounds; i++ {
+			h.Reset()
+			h.Write(tmp)
+			err = bcryptHash(tmp, passwordSHA512, h.Sum(nil))
+			if err != nil {
+				return nil, err
+			}
+			for j := range out {
+				out[j] ^= tmp[j]
+			}
+		}
+		for i, b := range out { // the output blocks are interleaved
+			key[i*blocks+block-1] = b
+		}
+	}
+	return key[:keyLength], nil
+}
+
+func bcryptHash(out, passwordSHA512, saltSHA512 []byte) (err error) {
+	c, err := blowfish.NewSaltedCipher(passwordSHA512, saltSHA512)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 64; i++ {
+		blowfish.ExpandKey(saltSHA512, c)
+		blowfish.ExpandKey(passwordSHA512, c)
+	}
+	copy(out, bcryptPBKDFMagic)
+	for i := 0; i < bcryptPBKDFBlockSize; i += 8 {
+		for j := 0; j < 64; j++ {
+			c.Encrypt(out[i:i+8], out[i:i+8])
+		}
+	}
+	for i := 0; i < bcryptPBKDFBlockSize; i += 4 { // little endian words
+		out[i], out[i+1], out[i+2], out[i+3] = out[i+3], out[i+2], out[i+1], out[i]
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/techsek/derivatex/constants"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
+)
+
+// Child seeds only derive the passwords of the websites of a namespace, such as work/github.com for the namespace work,
+// so that a less trusted device doesn't get the seed deriving all the passwords. From the password derivation version 5,
+// the passwords of the websites of a namespace always derive from the child seed of the namespace, so that the parent
+// seed derives the same passwords as the child seed without recording anything in the database.
+
+// NamespaceDerivationVersion is the first password derivation version deriving the passwords of the websites
+// of a namespace from the child seed of the namespace
+const NamespaceDerivationVersion = 5
+
+// ChildSeedDerivationVersion is the version of the derivation of the child seeds
+const ChildSeedDerivationVersion = 1
+
+// childSeedDerivationSalt separates the child seeds from the passwords and any other secret derived from the seed
+const childSeedDerivationSalt = "derivatex child seed derivation v1"
+
+// NamespaceSeparator separates the namespace of a website from the rest of the website, as in work/github.com
+const NamespaceSeparator = "/"
+
+// ParseNamespace returns the namespace of a name such as work, work/ or work/*
+func ParseNamespace(name string) (namespace string, err error) {
+	namespace = strings.TrimSuffix(strings.TrimSuffix(name, "*"), NamespaceSeparator)
+	if namespace == "" || strings.TrimSpace(namespace) != namespace {
+		return "", errors.New("the namespace '" + name + "' is empty or starts or ends with spaces")
+	}
+	if strings.Contains(namespace, NamespaceSeparator) || strings.Contains(namespace, "*") {
+		return "", errors.New("the namespace '" + name + "' can't contain '" + NamespaceSeparator + "' or '*', namespaces can't be nested")
+	}
+	if !isNamespace(namespace) {
+		return "", errors.New("the namespace '" + name + "' can't contain ':' or '.', so that it is never the scheme or the domain of a website")
+	}
+	return namespace, nil
+}
+
+// isNamespace returns true if the part of a website before its first separator is a namespace,
+// which excludes the scheme of https://github.com and the domain of github.com/login
+func isNamespace(namespace string) bool {
+	return namespace != "" && strings.TrimSpace(namespace) == namespace && !strings.ContainsAny(namespace, ":.*")
+}
+
+// websiteNamespace returns the part of the website before its first separator, such as work for work/github.com,
+// or an empty string if the website has no namespace
+func websiteNamespace(website string) (namespace string) {
+	i := strings.Index(website, NamespaceSeparator)
+	if i <= 0 || i == len(website)-len(NamespaceSeparator) || !isNamespace(website[:i]) {
+		return ""
+	}
+	return website[:i]
+}
+
+// DeriveChildSeed derives the child seed of the namespace with HKDF-SHA3-256 from the seed, which can't be
+// computed back from the child seed
+func DeriveChildSeed(seed *[]byte, namespace string, derivationVersion uint16) (childSeed *[]byte, err error) {
+	if derivationVersion != ChildSeedDerivationVersion {
+		return nil, errors.New("Child seed derivation version " + strconv.FormatUint(uint64(derivationVersion), 10) + " is not supported by this program, please update it")
+	}
+	childSeed = new([]byte)
+	*childSeed = make([]byte, constants.ArgonDigestSize)
+	io.ReadFull(hkdf.New(sha3.New256, *seed, []byte(childSeedDerivationSalt), lengthPrefixed(namespace)), *childSeed)
+	return childSeed, nil
+}
+
+// NewChildSeedFile returns an unprotected seed file of the child seed of the namespace, with the default user of the parent
+func NewChildSeedFile(parent *SeedFileType, namespace string, childSeed *[]byte) *SeedFileType {
+	seedFile := NewSeedFile(parent.DefaultUser, childSeed)
+	seedFile.SeedSalt = seedSaltParent
+	seedFile.Namespace = namespace
+	return seedFile
+}
+
+// IsChild returns true if the seed only derives the passwords of the websites of its namespace
+func (seedFile *SeedFileType) IsChild() bool {
+	return seedFile.Namespace != ""
+}
+
+// CheckWebsiteNamespace returns an error if the seed doesn't derive the passwords of the website,
+// which must be in the namespace of a child seed
+func (seedFile *SeedFileType) CheckWebsiteNamespace(website string) error {
+	if seedFile.IsChild() && websiteNamespace(website) != seedFile.Namespace {
+		return errors.New("This child seed only derives the passwords of the websites of the namespace " +
+			seedFile.Namespace + ", such as " + seedFile.Namespace + NamespaceSeparator + "github.com")
+	}
+	return nil
+}
+
+// WebsiteSeed returns a copy of the seed deriving the passwords of the website with the password derivation version.
+// This is the decrypted seed for the websites of the namespace of a child seed file, or the child seed of the namespace
+// of the website from the version 5, or else the decrypted seed.
+func (seedFile *SeedFileType) WebsiteSeed(seed *[]byte, website string, passwordDerivationVersion uint16) (websiteSeed *[]byte, err error) {
+	err = seedFile.CheckWebsiteNamespace(website)
+	if err != nil {
+		return nil, err
+	}
+	if seedFile.IsChild() {
+		if passwordDerivationVersion < NamespaceDerivationVersion {
+			return nil, errors.New("A child seed only derives the passwords of the password derivation version " +
+				strconv.Itoa(NamespaceDerivationVersion) + " or above")
+		}
+		websiteSeed = new([]byte)
+		*websiteSeed = append([]byte{}, *seed...)
+		return websiteSeed, nil
+	}
+	return deriveWebsiteSeed(seed, website, passwordDerivationVersion)
+}
+
+// deriveWebsiteSeed returns the child seed of the namespace of the website from the version 5,
+// or else a copy of the seed
+func deriveWebsiteSeed(seed *[]byte, website string, passwordDerivationVersion uint16) (websiteSeed *[]byte, err error) {
+	if namespace := websiteNamespace(website); namespace != "" && passwordDerivationVersion >= NamespaceDerivationVersion {
+		return DeriveChildSeed(seed, namespace, ChildSeedDerivationVersion)
+	}
+	websiteSeed = new([]byte)
+	*websiteSeed = append([]byte{}, *seed...)
+	return websiteSeed, nil
+}
+
+// IsPasswordDerivationOutdated returns true if the latest password derivation version derives the password
+// of the website differently, the version 5 only changing the passwords of the websites of a namespace
+func IsPasswordDerivationOutdated(website string, passwordDerivationVersion uint16) bool {
+	if passwordDerivationVersion == NamespaceDerivationVersion-1 && websiteNamespace(website) == "" {
+		return false
+	}
+	return passwordDerivationVersion < constants.PasswordDerivationVersion
+}
+
+// StripWebsiteNamespace returns the website without its namespace, such as github.com for work/github.com
+func StripWebsiteNamespace(website string) string {
+	if namespace := websiteNamespace(website); namespace != "" {
+		return website[len(namespace)+len(NamespaceSeparator):]
+	}
+	return website
+}
+
+// CountNamespaceIdentifications returns the number of identifications of the websites of the namespace
+// with a password derivation version before the version 5, whose passwords don't derive from the child seed
+func CountNamespaceIdentifications(namespace string) (count int, err error) {
+	err = database.QueryRow("SELECT COUNT(*) FROM identifications WHERE instr(website, ?) = 1 AND program_version < ?",
+		namespace+NamespaceSeparator, NamespaceDerivationVersion).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseNamespace(t *testing.T) {
+	cases := map[string]string{
+		"work":    "work",
+		"work/":   "work",
+		"work/*":  "work",
+		"my work": "my work",
+	}
+	for name, expected := range cases {
+		namespace, err := ParseNamespace(name)
+		if err != nil || namespace != expected {
+			t.Errorf("ParseNamespace(%q) == %q, %v want %q", name, namespace, err, expected)
+		}
+	}
+	for _, name := range []string{"", "/", "/*", " work", "work/eng", "work/eng/*", "w*rk", "https:", "github.com"} {
+		if _, err := ParseNamespace(name); err == nil {
+			t.Errorf("ParseNamespace(%q) should fail", name)
+		}
+	}
+}
+
+func Test_websiteNamespace(t *testing.T) {
+	cases := map[string]string{
+		"work/github.com":     "work",
+		"work/eng/github.com": "work",
+		"github.com":          "",
+		"/github.com":         "",
+		"work/":               "",
+		"https://github.com":  "",
+		"github.com/login":    "",
+		" work/github.com":    "",
+	}
+	for website, expected := range cases {
+		if namespace := websiteNamespace(website); namespace != expected {
+			t.Errorf("websiteNamespace(%q) == %q want %q", website, namespace, expected)
+		}
+	}
+}
+
+func TestDeriveChildSeed(t *testing.T) {
+	seed := []byte{1, 2, 3, 4}
+	childSeed, err := DeriveChildSeed(&seed, "work", ChildSeedDerivationVersion)
+	const expected = "18cdc990ec5b627cb54b53e1e2f63511d0a6a9efcc5ed5fefa0e66690d73b3ae69c4e7793dc34c27d4f129b0e63386cb321fbcdec321ca27307c99681be95121"
+	if err != nil || hex.EncodeToString(*childSeed) != expected {
+		t.Errorf("DeriveChildSeed() == %x, %v want %s", *childSeed, err, expected)
+	}
+	other, _ := DeriveChildSeed(&seed, "home", ChildSeedDerivationVersion)
+	if bytes.Equal(*childSeed, *other) {
+		t.Errorf("DeriveChildSeed() is the same for different namespaces")
+	}
+	if _, err = DeriveChildSeed(&seed, "work", 2); err == nil {
+		t.Errorf("DeriveChildSeed() with an unknown version should fail")
+	}
+}
+
+func Test_serializeParseChildSeed(t *testing.T) {
+	parent := NewSeedFile("a@a", &[]byte{1, 2, 3, 4})
+	childFile := NewChildSeedFile(parent, "work", &[]byte{17, 5, 2, 85, 178, 255, 0, 29})
+	if !childFile.IsChild() || parent.IsChild() {
+		t.Errorf("IsChild() is wrong for the parent or the child seed file")
+	}
+	if bytes.Contains(parent.header(), []byte("Namespace")) {
+		t.Errorf("header() of a seed file without namespace contains a namespace")
+	}
+	out, err := parseSeed(childFile.serialize())
+	if err != nil {
+		t.Fatalf("parseSeed(serialize()) - %s", err)
+	}
+	if !reflect.DeepEqual(out, childFile) {
+		t.Errorf("parseSeed(serialize()) == %v want %v", out, childFile)
+	}
+}
+
+func TestWebsiteSeed(t *testing.T) {
+	// the parent derives the passwords of the namespace without anything recorded in a database
+	seed := []byte{1, 2, 3, 4}
+	parent := NewSeedFile("a@a", &seed)
+	childSeed, _ := DeriveChildSeed(&seed, "work", ChildSeedDerivationVersion)
+	childFile := NewChildSeedFile(parent, "work", childSeed)
+
+	parentSeed, err := parent.WebsiteSeed(&seed, "work/github.com", NamespaceDerivationVersion)
+	if err != nil || !bytes.Equal(*parentSeed, *childSeed) {
+		t.Errorf("WebsiteSeed() of the parent == %x, %v want the child seed", *parentSeed, err)
+	}
+	websiteSeed, err := childFile.WebsiteSeed(childSeed, "work/github.com", NamespaceDerivationVersion)
+	if err != nil || !bytes.Equal(*websiteSeed, *childSeed) {
+		t.Errorf("WebsiteSeed() of the child == %x, %v want the child seed", *websiteSeed, err)
+	}
+	parentDigest := MakePasswordDigest(parentSeed, "work/github.com", "a@a", NamespaceDerivationVersion)
+	childDigest := MakePasswordDigest(websiteSeed, "work/github.com", "a@a", NamespaceDerivationVersion)
+	if *parentDigest != *childDigest {
+		t.Errorf("MakePasswordDigest() is different for the parent and the child seeds")
+	}
+	for _, c := range []struct {
+		website string
+		version uint16
+	}{{"work/github.com", 4}, {"github.com", NamespaceDerivationVersion}, {"work/", NamespaceDerivationVersion},
+		{"https://github.com", NamespaceDerivationVersion}, {"github.com/login", NamespaceDerivationVersion}} {
+		websiteSeed, err = parent.WebsiteSeed(&seed, c.website, c.version)
+		if err != nil || !bytes.Equal(*websiteSeed, seed) {
+			t.Errorf("WebsiteSeed() of the parent for %s of version %d == %x, %v want the seed", c.website, c.version, *websiteSeed, err)
+		}
+	}
+	websiteSeed, _ = parent.WebsiteSeed(&seed, "home/github.com", NamespaceDerivationVersion)
+	if bytes.Equal(*websiteSeed, seed) || bytes.Equal(*websiteSeed, *childSeed) {
+		t.Errorf("WebsiteSeed() of the parent for another namespace == %x want its child seed", *websiteSeed)
+	}
+	for _, website := range []string{"github.com", "home/github.com", "work"} {
+		if _, err = childFile.WebsiteSeed(childSeed, website, NamespaceDerivationVersion); err == nil {
+			t.Errorf("WebsiteSeed() of the child for %s should fail", website)
+		}
+	}
+	if _, err = childFile.WebsiteSeed(childSeed, "work/github.com", 4); err == nil {
+		t.Errorf("WebsiteSeed() of the child for the version 4 should fail")
+	}
+}
+
+func TestIsPasswordDerivationOutdated(t *testing.T) {
+	cases := []struct {
+		website  string
+		version  uint16
+		outdated bool
+	}{
+		{"github.com", 3, true},
+		{"github.com", 4, false},
+		{"work/github.com", 4, true},
+		{"work/github.com", NamespaceDerivationVersion, false},
+	}
+	for _, c := range cases {
+		if outdated := IsPasswordDerivationOutdated(c.website, c.version); outdated != c.outdated {
+			t.Errorf("IsPasswordDerivationOutdated(%s, %d) == %t want %t", c.website, c.version, outdated, c.outdated)
+		}
+	}
+}
+
+func TestStripWebsiteNamespace(t *testing.T) {
+	cases := map[string]string{
+		"work/github.com":    "github.com",
+		"github.com":         "github.com",
+		"https://github.com": "https://github.com",
+		"github.com/login":   "github.com/login",
+	}
+	for website, expected := range cases {
+		if out := StripWebsiteNamespace(website); out != expected {
+			t.Errorf("StripWebsiteNamespace(%q) == %q want %q", website, out, expected)
+		}
+	}
+}
+
+func TestCountNamespaceIdentifications(t *testing.T) {
+	dir, err := ioutil.TempDir("", "derivatex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { vaultDirectory, vaultProfile = "", "" }()
+	_, err = SetVault(dir, "", false)
+	if err != nil {
+		t.Fatalf("SetVault() - %s", err)
+	}
+	err = InitiateDatabaseIfNeeded()
+	if err != nil {
+		t.Fatalf("InitiateDatabaseIfNeeded() - %s", err)
+	}
+	defer database.Close()
+
+	for website, version := range map[string]uint16{"home/github.com": 4, "home/gitlab.com": NamespaceDerivationVersion} {
+		err = InsertIdentification(IdentificationType{Website: website, User: "a@a", PasswordDerivationVersion: version})
+		if err != nil {
+			t.Fatalf("InsertIdentification() - %s", err)
+		}
+	}
+	for namespace, expected := range map[string]int{"home": 1, "hom": 0, "work": 0} {
+		if count, err := CountNamespaceIdentifications(namespace); err != nil || count != expected {
+			t.Errorf("CountNamespaceIdentifications(%s) == %d, %v want %d", namespace, count, err, expected)
+		}
+	}
+}
@@ -44,3 +44,16 @@ func clearAndTrim(secretPtr *[]byte, n int) {
 	}
 	*secretPtr = (*secretPtr)[n:]
 }
+
+// clearTail clears the last n bytes of the secret and removes them
+func clearTail(secretPtr *[]byte, n int) {
+	if L := len(*secretPtr); L < n {
+		n = L
+	}
+	for i := len(*secretPtr) - n; i < len(*secretPtr); i++ {
+		(*secretPtr)[i] = byte(0)
+		(*secretPtr)[i] = byte(1)
+		(*secretPtr)[i] = byte(0)
+	}
+	*secretPtr = (*secretPtr)[:len(*secretPtr)-n]
+}
@@ -0,0 +1,306 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/techsek/derivatex/constants"
+)
+
+// Classes of characters of the constraints
+const (
+	ClassLowercase = "lowercase"
+	ClassUppercase = "uppercase"
+	ClassDigit     = "digit"
+	ClassSymbol    = "symbol"
+)
+
+var constraintClasses = map[string]asciiType{
+	ClassLowercase: asciiLowercase,
+	ClassUppercase: asciiUppercase,
+	ClassDigit:     asciiDigit,
+	ClassSymbol:    asciiSymbol,
+}
+
+const passwordDerivationV4Constraints = "constraints"
+
+// ClassCountType limits the number of characters of a class in a password, a Max of 0 meaning no maximum.
+// A class is forbidden by forbidding its characters.
+type ClassCountType struct {
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// CharacterConstraintsType is the canonical model of the characters of a password, stored as JSON in the
+// identification. Forbidden characters are never used, the characters are restricted to Allowed if it is
+// not empty, each Required character is used at least once and Counts limit the characters of each class.
+// Character sets are sorted without duplicates, and counts equal to zero are removed.
+type CharacterConstraintsType struct {
+	Allowed   string                    `json:"allowed,omitempty"`
+	Forbidden string                    `json:"forbidden,omitempty"`
+	Required  string                    `json:"required,omitempty"`
+	Counts    map[string]ClassCountType `json:"counts,omitempty"`
+}
+
+// NewCharacterConstraints returns the constraints forbidding the characters of the generate flags,
+// forbidding the characters of constants.Uppercases for noUppercase as previous versions did
+func NewCharacterConstraints(noSymbol, noDigit, noUppercase, noLowercase bool, excludeCharacters string) CharacterConstraintsType {
+	var forbidden string
+	if noSymbol {
+		forbidden += constants.Symbols
+	}
+	if noDigit {
+		forbidden += constants.Digits
+	}
+	if noUppercase {
+		forbidden += constants.Uppercases
+	}
+	if noLowercase {
+		forbidden += constants.Lowercases
+	}
+	return CharacterConstraintsType{Forbidden: canonicalCharacterSet(forbidden + excludeCharacters)}
+}
+
+// canonicalCharacterSet returns the sorted printable ASCII characters of the set, without duplicates.
+// Other characters are dropped as no password ever contained them.
+func canonicalCharacterSet(set string) string {
+	var printable []byte
+	for i := 0; i < len(set); i++ {
+		if set[i] >= 33 && set[i] <= 126 {
+			printable = append(printable, set[i])
+		}
+	}
+	return characterSetUnion(string(printable))
+}
+
+// Canonical returns the constraints with sorted sets without duplicates and without the counts equal to zero
+func (constraints CharacterConstraintsType) Canonical() CharacterConstraintsType {
+	canonical := CharacterConstraintsType{
+		Allowed:   canonicalCharacterSet(constraints.Allowed),
+		Forbidden: canonicalCharacterSet(constraints.Forbidden),
+		Required:  canonicalCharacterSet(constraints.Required),
+	}
+	for class, count := range constraints.Counts {
+		if count != (ClassCountType{}) {
+			if canonical.Counts == nil {
+				canonical.Counts = make(map[string]ClassCountType)
+			}
+			canonical.Counts[class] = count
+		}
+	}
+	return canonical
+}
+
+// IsEmpty returns true if no character is constrained
+func (constraints CharacterConstraintsType) IsEmpty() bool {
+	return constraints.Allowed == "" && constraints.Forbidden == "" && constraints.Required == "" && len(constraints.Counts) == 0
+}
+
+// OnlyForbids returns true if the constraints only forbid characters, as supported by all the password derivation versions
+func (constraints CharacterConstraintsType) OnlyForbids() bool {
+	return constraints.Allowed == "" && constraints.Required == "" && len(constraints.Counts) == 0
+}
+
+// String returns the canonical constraints as JSON, or an empty string if no character is constrained
+func (constraints CharacterConstraintsType) String() string {
+	canonical := constraints.Canonical()
+	if canonical.IsEmpty() {
+		return ""
+	}
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(canonical) // map keys are sorted
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// ParseCharacterConstraints reads constraints serialized with String
+func ParseCharacterConstraints(s string) (constraints CharacterConstraintsType, err error) {
+	if s == "" {
+		return constraints, nil
+	}
+	err = json.Unmarshal([]byte(s), &constraints)
+	if err != nil {
+		return constraints, errors.New("Character constraints '" + s + "' are invalid: " + err.Error())
+	}
+	constraints = constraints.Canonical()
+	return constraints, constraints.Validate()
+}
+
+// Validate verifies the classes of the counts and that the required characters and the counts can be satisfied together
+func (constraints CharacterConstraintsType) Validate() error {
+	allowed := constraints.allowedCharacters()
+	for i := 0; i < len(constraints.Required); i++ {
+		c := constraints.Required[i]
+		if bytes.IndexByte(allowed[byteASCIIType(c)], c) < 0 {
+			return errors.New("Required character '" + string(c) + "' is not allowed")
+		}
+	}
+	for class, count := range constraints.Counts {
+		t, ok := constraintClasses[class]
+		if !ok {
+			return errors.New("Character class '" + class + "' is not supported")
+		}
+		if count.Min < 0 || count.Max < 0 || (count.Max > 0 && count.Min > count.Max) {
+			return errors.New("Count of " + class + " characters " + count.String() + " is not valid")
+		}
+		if count.Min > 0 && len(allowed[t]) == 0 {
+			return errors.New("At least " + strconv.Itoa(count.Min) + " " + class + " characters are required but none is allowed")
+		}
+		if count.Max > 0 && constraints.requiredOfType(t) > count.Max {
+			return errors.New("More " + class + " characters are required than the maximum of " + strconv.Itoa(count.Max))
+		}
+	}
+	return nil
+}
+
+func (constraints CharacterConstraintsType) requiredOfType(t asciiType) (n int) {
+	for i := 0; i < len(constraints.Required); i++ {
+		if byteASCIIType(constraints.Required[i]) == t {
+			n++
+		}
+	}
+	return n
+}
+
+func (count ClassCountType) String() string {
+	s := strconv.Itoa(count.Min) + "-"
+	if count.Max > 0 {
+		s += strconv.Itoa(count.Max)
+	}
+	return s
+}
+
+// ParseClassCount reads a count of characters of a class such as 'digit=2-4', 'symbol=1-' or 'uppercase=-3'
+func ParseClassCount(s string) (class string, count ClassCountType, err error) {
+	parts := strings.SplitN(s, "=", 2)
+	bounds := []string{""}
+	if len(parts) == 2 {
+		bounds = strings.SplitN(parts[1], "-", 2)
+	}
+	if len(parts) != 2 || len(bounds) != 2 {
+		return "", ClassCountType{}, errors.New("Count '" + s + "' must be written as class=min-max, i.e. digit=2-4")
+	}
+	class = strings.ToLower(strings.TrimSpace(parts[0]))
+	if _, ok := constraintClasses[class]; !ok {
+		return "", count, errors.New("Character class '" + class + "' is not supported, use lowercase, uppercase, digit or symbol")
+	}
+	for i, bound := range bounds {
+		if bound == "" {
+			continue
+		}
+		n, err := strconv.Atoi(bound)
+		if err != nil || n < 0 {
+			return "", ClassCountType{}, errors.New("Count '" + s + "' must be written as class=min-max, i.e. digit=2-4")
+		}
+		if i == 0 {
+			count.Min = n
+		} else {
+			count.Max = n
+		}
+	}
+	if count.Max > 0 && count.Min > count.Max {
+		return "", ClassCountType{}, errors.New("Count '" + s + "' has a minimum bigger than its maximum")
+	}
+	return class, count, nil
+}
+
+// Description returns the constraints for display, only the forbidden characters if that is all they contain
+func (constraints CharacterConstraintsType) Description() string {
+	if constraints.OnlyForbids() {
+		return constraints.Forbidden
+	}
+	var properties []string
+	if constraints.Allowed != "" {
+		properties = append(properties, "allowed: "+constraints.Allowed)
+	}
+	if constraints.Forbidden != "" {
+		properties = append(properties, "forbidden: "+constraints.Forbidden)
+	}
+	if constraints.Required != "" {
+		properties = append(properties, "required: "+constraints.Required)
+	}
+	var classes []string
+	for class := range constraints.Counts {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		properties = append(properties, class+": "+constraints.Counts[class].String())
+	}
+	return strings.Join(properties, "; ")
+}
+
+// allowedCharacters returns the allowed printable ASCII characters of each type. A type is unallowed as a whole
+// if the forbidden characters contain all the characters of its flag, as the noUppercase flag forbids constants.Uppercases
+// which lacks J, U and W, otherwise the allowed characters are taken from all the characters of the type.
+func (constraints CharacterConstraintsType) allowedCharacters() (allowed map[asciiType][]byte) {
+	forbidden := BuildUnallowedCharacters(CharacterConstraintsType{Forbidden: constraints.Forbidden})
+	unallowedCharacters := BuildUnallowedCharacters(constraints)
+	allowed = make(map[asciiType][]byte)
+	for b := byte(33); b <= 126; b++ {
+		t := byteASCIIType(b)
+		if !forbidden.isTypeUnallowed(t) && strings.IndexByte(unallowedCharacters[t], b) < 0 {
+			allowed[t] = append(allowed[t], b)
+		}
+	}
+	return allowed
+}
+
+// IsAnythingAllowed returns true if the constraints allow at least one character
+func (constraints CharacterConstraintsType) IsAnythingAllowed() bool {
+	return len(constraints.allowedCharacters()) > 0
+}
+
+// satisfyConstraintsV4 draws a password satisfying all the constraints from a HKDF stream of the identification key and round.
+// Each required character and each character needed to reach the minimum count of a class gets a slot, the other slots
+// are drawn uniformly among the allowed characters of the classes below their maximum count, then the slots are shuffled.
+func satisfyConstraintsV4(passwordDigest *[32]byte, passwordLength int, round uint16, constraints CharacterConstraintsType) (password string, err error) {
+	allowed := constraints.allowedCharacters()
+	types := []asciiType{asciiLowercase, asciiUppercase, asciiDigit, asciiSymbol}
+	counts := make(map[asciiType]ClassCountType)
+	for class, count := range constraints.Counts {
+		counts[constraintClasses[class]] = count
+	}
+	info := []byte(passwordDerivationV4Constraints)
+	info = append(info, byte(round>>8), byte(round))
+	stream := newHKDFStream((*passwordDigest)[:], info)
+
+	b := []byte(constraints.Required)
+	used := make(map[asciiType]int)
+	for _, c := range b {
+		used[byteASCIIType(c)]++
+	}
+	for _, t := range types {
+		for used[t] < counts[t].Min {
+			b = append(b, allowed[t][stream.uniformInt(len(allowed[t]))])
+			used[t]++
+		}
+	}
+	if len(b) > passwordLength {
+		return "", errors.New("The password length " + strconv.Itoa(passwordLength) + " is smaller than the " + strconv.Itoa(len(b)) + " characters required by the constraints")
+	}
+	for len(b) < passwordLength {
+		var candidates []byte
+		for _, t := range types {
+			if counts[t].Max == 0 || used[t] < counts[t].Max {
+				candidates = append(candidates, allowed[t]...)
+			}
+		}
+		if len(candidates) == 0 {
+			return "", errors.New("The password length " + strconv.Itoa(passwordLength) + " is bigger than the maximum counts of the allowed characters")
+		}
+		c := candidates[stream.uniformInt(len(candidates))]
+		b = append(b, c)
+		used[byteASCIIType(c)]++
+	}
+	for i := len(b) - 1; i > 0; i-- { // Fisher-Yates shuffle
+		j := stream.uniformInt(i + 1)
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/techsek/derivatex/constants"
+)
+
+func TestCharacterConstraintsString(t *testing.T) {
+	constraints := CharacterConstraintsType{
+		Allowed:   "cbaa123",
+		Forbidden: "3 é",
+		Required:  "1a",
+		Counts:    map[string]ClassCountType{ClassDigit: {Min: 1, Max: 2}, ClassSymbol: {}},
+	}
+	const expected = `{"allowed":"123abc","forbidden":"3","required":"1a","counts":{"digit":{"min":1,"max":2}}}`
+	if out := constraints.String(); out != expected {
+		t.Errorf("String() == %s want %s", out, expected)
+	}
+	parsed, err := ParseCharacterConstraints(expected)
+	if err != nil {
+		t.Fatalf("ParseCharacterConstraints(%s) - %s", expected, err)
+	}
+	if out := parsed.String(); out != expected {
+		t.Errorf("ParseCharacterConstraints(%s).String() == %s", expected, out)
+	}
+	if out := (CharacterConstraintsType{Forbidden: " "}).String(); out != "" {
+		t.Errorf("String() of empty constraints == %q want an empty string", out)
+	}
+	for _, s := range []string{"{", `{"counts":{"emoji":{"min":1}}}`, `{"forbidden":"a","required":"a"}`, `{"counts":{"digit":{"min":3,"max":2}}}`, `{"required":"123","counts":{"digit":{"max":2}}}`} {
+		_, err := ParseCharacterConstraints(s)
+		if err == nil {
+			t.Errorf("ParseCharacterConstraints(%s) should fail", s)
+		}
+	}
+}
+
+func TestCharacterConstraintsMigration(t *testing.T) {
+	// the unallowed characters were saved concatenating the characters of each type in a random order
+	serialized := []string{
+		constants.Digits + constants.Symbols + "aeiou",
+		"aeiou" + constants.Symbols + constants.Digits,
+		constants.Symbols + "aeiou" + constants.Digits,
+	}
+	seed := []byte{1, 2, 3, 4}
+	var expected string
+	for version := uint16(1); version <= constants.PasswordDerivationVersion; version++ {
+		for i, unallowed := range serialized {
+			constraints := NewCharacterConstraints(false, false, false, false, unallowed)
+			if out := constraints.String(); out != NewCharacterConstraints(true, true, false, false, "aeiou").String() {
+				t.Errorf("NewCharacterConstraints(%q).String() == %s", unallowed, out)
+			}
+			digest := MakePasswordDigest(&seed, "website", "user", version)
+			password, err := SatisfyPassword(digest, 16, 1, constraints, version)
+			if err != nil {
+				t.Fatalf("SatisfyPassword() - %s", err)
+			}
+			if i == 0 {
+				expected = password
+			} else if password != expected {
+				t.Errorf("SatisfyPassword() of version %d == %s want %s", version, password, expected)
+			}
+		}
+	}
+}
+
+func TestSatisfyPasswordConstraints(t *testing.T) {
+	seed := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	constraints := CharacterConstraintsType{
+		Allowed:   "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789-_!",
+		Forbidden: "_",
+		Required:  "!",
+		Counts:    map[string]ClassCountType{ClassDigit: {Min: 2, Max: 3}, ClassUppercase: {Min: 1}, ClassSymbol: {Max: 2}},
+	}
+	for round := uint16(1); round <= 50; round++ {
+		digest := MakePasswordDigest(&seed, "website", "user", 4)
+		password, err := SatisfyPassword(digest, 12, round, constraints, 4)
+		if err != nil {
+			t.Fatalf("SatisfyPassword() - %s", err)
+		}
+		again, _ := SatisfyPassword(digest, 12, round, constraints, 4)
+		if password != again {
+			t.Errorf("SatisfyPassword() is not deterministic: %q and %q", password, again)
+		}
+		counts := make(map[asciiType]int)
+		for i := 0; i < len(password); i++ {
+			counts[byteASCIIType(password[i])]++
+			if !strings.ContainsRune(constraints.Allowed, rune(password[i])) || password[i] == '_' {
+				t.Errorf("SatisfyPassword() returned %q with characters not allowed", password)
+			}
+		}
+		if len(password) != 12 || !strings.Contains(password, "!") || counts[asciiDigit] < 2 || counts[asciiDigit] > 3 ||
+			counts[asciiUppercase] < 1 || counts[asciiSymbol] > 2 {
+			t.Errorf("SatisfyPassword() returned %q not satisfying the constraints", password)
+		}
+	}
+	digest := MakePasswordDigest(&seed, "website", "user", 4)
+	password, _ := SatisfyPassword(digest, 12, 1, constraints, 4)
+	if password != "8UHAk3xSu4!n" {
+		t.Errorf("SatisfyPassword() == %q want %q", password, "8UHAk3xSu4!n")
+	}
+}
+
+func TestSatisfyPasswordConstraintsErrors(t *testing.T) {
+	one := []byte{1}
+	digest := MakePasswordDigest(&one, "website", "user", 4)
+	cases := []struct {
+		constraints CharacterConstraintsType
+		length      uint16
+		version     uint16
+	}{
+		{CharacterConstraintsType{Required: "a"}, 10, 3},
+		{CharacterConstraintsType{Allowed: "ab"}, 10, 2},
+		{CharacterConstraintsType{Required: "abc", Counts: map[string]ClassCountType{ClassDigit: {Min: 2}}}, 4, 4},
+		{CharacterConstraintsType{Allowed: "ab12", Counts: map[string]ClassCountType{ClassDigit: {Max: 1}, ClassLowercase: {Max: 2}}}, 4, 4},
+		{NewCharacterConstraints(false, false, true, false, "JUW"), 4, 4},
+		{CharacterConstraintsType{Required: "J", Forbidden: constants.Uppercases}, 4, 4},
+	}
+	for _, c := range cases {
+		if c.constraints.Required == "" && len(c.constraints.Counts) == 0 {
+			c.constraints.Counts = map[string]ClassCountType{ClassUppercase: {Min: 1}}
+		}
+		_, err := SatisfyPassword(digest, c.length, 1, c.constraints, c.version)
+		if err == nil {
+			t.Errorf("SatisfyPassword(%s, %d, version %d) should fail", c.constraints, c.length, c.version)
+		}
+	}
+}
+
+func TestSatisfyPasswordAllowedUppercases(t *testing.T) {
+	// J, U and W are not in constants.Uppercases but are allowed as any other uppercase letter
+	seed := []byte{1, 2, 3, 4}
+	digest := MakePasswordDigest(&seed, "website", "user", 4)
+	emitted := false
+	for round := uint16(1); round <= 20; round++ {
+		password, err := SatisfyPassword(digest, 12, round, CharacterConstraintsType{Allowed: "abJ"}, 4)
+		if err != nil || len(password) != 12 || strings.Trim(password, "abJ") != "" {
+			t.Fatalf("SatisfyPassword() with allowed abJ == %q, %v", password, err)
+		}
+		emitted = emitted || strings.Contains(password, "J")
+	}
+	if !emitted {
+		t.Errorf("SatisfyPassword() with allowed abJ never uses J")
+	}
+	constraints := CharacterConstraintsType{Allowed: "aJ", Required: "J"}
+	if err := constraints.Validate(); err != nil {
+		t.Errorf("Validate() of %s - %s", constraints, err)
+	}
+	password, err := SatisfyPassword(digest, 8, 1, constraints, 4)
+	if err != nil || !strings.Contains(password, "J") {
+		t.Errorf("SatisfyPassword() of %s == %q, %v", constraints, password, err)
+	}
+	password, err = SatisfyPassword(digest, 8, 1, CharacterConstraintsType{Allowed: "JUW"}, 4)
+	if err != nil || len(password) != 8 || strings.Trim(password, "JUW") != "" {
+		t.Errorf("SatisfyPassword() with allowed JUW == %q, %v", password, err)
+	}
+	nothing := NewCharacterConstraints(true, true, true, true, "")
+	for version := uint16(1); version <= constants.PasswordDerivationVersion; version++ {
+		if password, err = SatisfyPassword(digest, 8, 1, nothing, version); err == nil {
+			t.Errorf("SatisfyPassword() of version %d without allowed characters == %q and should fail", version, password)
+		}
+	}
+}
+
+func TestParseClassCount(t *testing.T) {
+	cases := []struct {
+		s     string
+		class string
+		count ClassCountType
+		err   bool
+	}{
+		{"digit=2-4", ClassDigit, ClassCountType{Min: 2, Max: 4}, false},
+		{"Symbol=1-", ClassSymbol, ClassCountType{Min: 1}, false},
+		{"uppercase=-3", ClassUppercase, ClassCountType{Max: 3}, false},
+		{"lowercase=0-0", ClassLowercase, ClassCountType{}, false},
+		{"digit=2", "", ClassCountType{}, true},
+		{"digit", "", ClassCountType{}, true},
+		{"emoji=1-2", "", ClassCountType{}, true},
+		{"digit=4-2", "", ClassCountType{}, true},
+		{"digit=a-2", "", ClassCountType{}, true},
+	}
+	for _, c := range cases {
+		class, count, err := ParseClassCount(c.s)
+		if (err != nil) != c.err || class != c.class || count != c.count {
+			t.Errorf("ParseClassCount(%q) == %q, %v, %v", c.s, class, count, err)
+		}
+	}
+}
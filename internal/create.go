@@ -1,12 +1,10 @@
 package internal
 
 import (
-	"encoding/base64"
+	"crypto/rand"
 	"errors"
 	"io"
-	"io/ioutil"
 	"os"
-	"path/filepath"
 	"strconv"
 	"time"
 
@@ -23,46 +21,114 @@ func GetArgonTimePerRound() int64 {
 }
 
 func CreateSeed(masterPasswordSHA3 *[32]byte, birthdateSHA3 *[32]byte) (seed *[]byte) {
+	return CreateSeedWithSalt(masterPasswordSHA3, (*birthdateSHA3)[:], DefaultSeedArgonParams)
+}
+
+// CreateSeedWithSalt derives the seed from the master password and a salt, which is either
+// the SHA3 digest of the birthdate or a random salt, with the Argon2ID parameters
+func CreateSeedWithSalt(masterPasswordSHA3 *[32]byte, salt []byte, params ArgonParamsType) (seed *[]byte) {
 	seed = new([]byte)
-	*seed = argon2.IDKey((*masterPasswordSHA3)[:], (*birthdateSHA3)[:], constants.ArgonTimeCost, constants.ArgonMemoryMB*1024, constants.ArgonParallelism, constants.ArgonDigestSize)
+	*seed = argon2.IDKey((*masterPasswordSHA3)[:], salt, params.TimeCost, params.MemoryKB, params.Parallelism, constants.ArgonDigestSize)
 	return seed
 }
 
-func WriteSeed(defaultUser string, protection string, seed *[]byte) error {
-	ex, err := os.Executable()
+func WriteSeed(seedFile *SeedFileType) error {
+	path, err := seedFilePath()
 	if err != nil {
 		return err
 	}
-	dir := filepath.Dir(ex)
-	var content = new([]byte)
-	*content = append(*content, []byte("Default user: "+defaultUser+"\n")...)
-	*content = append(*content, []byte("Protection: "+protection+"\n")...)
-	*content = append(*content, []byte("Secret Seed: ")...)
-	*content = append(*content, []byte(base64.StdEncoding.EncodeToString(*seed))...)
-	err = ioutil.WriteFile(dir+"/"+constants.SeedFilename, *content, 0644)
+	return WriteSeedTo(seedFile, path, 0644)
+}
+
+// WriteSeedTo writes the seed file to the file name instead of the seed file of the vault, i.e. a child seed file
+func WriteSeedTo(seedFile *SeedFileType, filename string, perm os.FileMode) (err error) {
+	content := seedFile.serialize()
+	err = writeFileAtomically(filename, content, perm)
 	ClearByteSlice(content)
 	return err
 }
 
-func EncryptSeed(seed *[]byte, passphrase *[]byte) (encryptedSeed *[]byte, err error) {
-	key := MakeKey(passphrase) // Argon2ID
+// EncryptSeed encrypts the seed with AES-256-GCM using a key derived from the passphrase
+// and the keyfile digest, either of which can be nil, the header of the seed file being
+// authenticated together with the seed
+func EncryptSeed(seedFile *SeedFileType, seed *[]byte, passphrase *[]byte, keyfileSHA3 *[32]byte) (err error) {
+	switch {
+	case passphrase != nil && keyfileSHA3 != nil:
+		seedFile.Protection = seedProtectionPassphraseKeyfile
+	case passphrase != nil:
+		seedFile.Protection = seedProtectionPassphrase
+	case keyfileSHA3 != nil:
+		seedFile.Protection = seedProtectionKeyfile
+	default:
+		ClearByteSlice(seed)
+		return errors.New("A passphrase or a keyfile is needed to protect the seed")
+	}
+	seedFile.PassphraseSalt = make([]byte, constants.PassphraseSaltSize)
+	_, err = io.ReadFull(rand.Reader, seedFile.PassphraseSalt)
+	if err != nil {
+		ClearByteSlice(passphrase)
+		ClearByteSlice(seed)
+		return err
+	}
+	seedFile.Version = constants.SeedFileVersion
+	seedFile.Cipher = seedCipherAESGCM
+	secret := makeSeedSecret(passphrase, keyfileSHA3)
 	ClearByteSlice(passphrase)
-	Checksumize(seed)
-	encryptedSeed, err = EncryptAES(seed, key, io.ReadFull)
+	key := MakeKey(secret, seedFile.PassphraseArgon, seedFile.PassphraseSalt) // Argon2ID
+	ClearByteSlice(secret)
+	encryptedSeed, err := EncryptAESGCM(seed, key, seedFile.header(), io.ReadFull)
 	ClearByteSlice(seed)
 	ClearByteArray32(key)
 	if err != nil {
 		ClearByteSlice(encryptedSeed)
-		return nil, err
+		return err
 	}
-	return encryptedSeed, nil
+	seedFile.Seed = encryptedSeed
+	return nil
 }
 
-func DecryptSeed(encryptedSeed *[]byte, passphrase *[]byte) (seed *[]byte, err error) {
-	key := MakeKey(passphrase) // Argon2ID
+// makeSeedSecret concatenates the protection factors given to be fed to Argon2ID,
+// the passphrase followed by the keyfile digest
+func makeSeedSecret(passphrase *[]byte, keyfileSHA3 *[32]byte) (secret *[]byte) {
+	secret = new([]byte)
+	if passphrase != nil {
+		*secret = append(*secret, *passphrase...)
+	}
+	if keyfileSHA3 != nil {
+		*secret = append(*secret, (*keyfileSHA3)[:]...)
+	}
+	return secret
+}
+
+// DecryptSeed decrypts the seed of the seed file using the factors it requires, the
+// passphrase and or the keyfile digest, and the cipher and Argon2ID parameters recorded in the seed file
+func DecryptSeed(seedFile *SeedFileType, passphrase *[]byte, keyfileSHA3 *[32]byte) (seed *[]byte, err error) {
+	if seedFile.Cipher != seedCipherAESGCM && seedFile.Cipher != seedCipherAESCFB {
+		ClearByteSlice(passphrase)
+		return nil, errors.New("Cipher '" + seedFile.Cipher + "' is not supported")
+	}
+	if !seedFile.RequiresPassphrase() {
+		ClearByteSlice(passphrase)
+		passphrase = nil
+	} else if passphrase == nil {
+		return nil, errors.New("A passphrase is required to decrypt the seed")
+	}
+	if !seedFile.RequiresKeyfile() {
+		keyfileSHA3 = nil
+	} else if keyfileSHA3 == nil {
+		ClearByteSlice(passphrase)
+		return nil, errors.New("A keyfile is required to decrypt the seed")
+	}
+	secret := makeSeedSecret(passphrase, keyfileSHA3)
 	ClearByteSlice(passphrase)
-	seed, err = DecryptAES(encryptedSeed, key)
-	ClearByteArray32(key)
+	key := MakeKey(secret, seedFile.PassphraseArgon, seedFile.PassphraseSalt) // Argon2ID
+	ClearByteSlice(secret)
+	defer ClearByteArray32(key)
+	if seedFile.Cipher == seedCipherAESGCM {
+		return DecryptAESGCM(seedFile.Seed, key, seedFile.header())
+	}
+	// Legacy unauthenticated encryption with a checksum to detect a wrong passphrase
+	seed, err = DecryptAES(seedFile.Seed, key)
 	if err != nil {
 		ClearByteSlice(seed)
 		return nil, err
@@ -75,41 +141,80 @@ func DecryptSeed(encryptedSeed *[]byte, passphrase *[]byte) (seed *[]byte, err e
 	return seed, nil
 }
 
-func CreateNonInteractive(masterPassword string, birthdate string, user string, passphrase string) (err error) {
-	// TODO mutable strings to clear them all
-	masterPasswordBytes := []byte(masterPassword)
-	safety, _ := EvaluatePassword(&masterPasswordBytes)
-	masterPasswordSHA3 := HashAndDestroy(&masterPasswordBytes)
+// RemoveSeedProtection sets the decrypted seed in the seed file without any protection
+func RemoveSeedProtection(seedFile *SeedFileType, seed *[]byte) {
+	seedFile.Version = constants.SeedFileVersion
+	seedFile.Protection = seedProtectionNone
+	seedFile.Cipher = seedCipherNone
+	seedFile.PassphraseSalt = []byte{}
+	seedFile.Seed = seed
+}
+
+// UpgradeSeed rewrites the seed file content with the format, cipher and
+// Argon2ID parameters of the current release, keeping the same protection factors
+func UpgradeSeed(seedFile *SeedFileType, passphrase *[]byte, keyfileSHA3 *[32]byte) (err error) {
+	if !seedFile.IsProtected() {
+		seedFile.Version = constants.SeedFileVersion
+		return nil
+	}
+	var passphraseCopy *[]byte
+	if seedFile.RequiresPassphrase() && passphrase != nil {
+		passphraseCopy = new([]byte)
+		*passphraseCopy = append(*passphraseCopy, *passphrase...)
+	}
+	if !seedFile.RequiresKeyfile() {
+		keyfileSHA3 = nil
+	}
+	seed, err := DecryptSeed(seedFile, passphrase, keyfileSHA3)
+	if err != nil {
+		ClearByteSlice(passphraseCopy)
+		return err
+	}
+	seedFile.PassphraseArgon = DefaultPassphraseArgonParams
+	return EncryptSeed(seedFile, seed, passphraseCopy, keyfileSHA3)
+}
+
+// CreateNonInteractive creates the seed file from the master password and birthdate with the same
+// password policy as the interactive creation, protecting it with the passphrase and or the keyfile
+// digest if they are not nil. All the secrets given are cleared.
+func CreateNonInteractive(masterPassword *[]byte, birthdate *[]byte, user string, passphrase *[]byte, keyfileSHA3 *[32]byte) (seedFile *SeedFileType, err error) {
+	defer ClearByteSlice(passphrase)
+	if user == "" {
+		ClearByteSlice(masterPassword)
+		ClearByteSlice(birthdate)
+		return nil, errors.New("A default user is required.")
+	}
+	safety, _ := EvaluatePassword(masterPassword)
+	masterPasswordSHA3 := HashAndDestroy(masterPassword)
 	if safety == 0 {
 		ClearByteArray32(masterPasswordSHA3)
-		return errors.New("Your password is not safe, please enter a more complicated password.")
+		ClearByteSlice(birthdate)
+		return nil, errors.New("Your password is not safe, please enter a more complicated password.")
 	}
-	// TODO: what to do for safety == 1 ?
-	birthdateBytes := []byte(birthdate)
-	if !DateIsValid(&birthdateBytes) {
-		ClearByteSlice(&birthdateBytes)
-		return errors.New("The birthdate you entered is not valid.")
+	if !DateIsValid(birthdate) {
+		ClearByteArray32(masterPasswordSHA3)
+		ClearByteSlice(birthdate)
+		return nil, errors.New("The birthdate you entered is not valid.")
 	}
-	birthdateSHA3 := HashAndDestroy(&birthdateBytes)
+	birthdateSHA3 := HashAndDestroy(birthdate)
 	seed := CreateSeed(masterPasswordSHA3, birthdateSHA3)
 	ClearByteArray32(masterPasswordSHA3)
 	ClearByteArray32(birthdateSHA3)
-	protection := "none"
-	if passphrase != "" {
-		protection = "passphrase"
-		passphraseBytes := []byte(passphrase)
-		encryptedSeed, err := EncryptSeed(seed, &passphraseBytes)
+	seedFile = NewSeedFile(user, seed)
+	if passphrase != nil || keyfileSHA3 != nil {
+		err = EncryptSeed(seedFile, seed, passphrase, keyfileSHA3)
 		if err != nil {
-			return errors.New("The following error occurred when encrypting the seed: " + err.Error())
+			ClearByteSlice(seed)
+			ClearByteSlice(seedFile.Seed)
+			return nil, errors.New("The following error occurred when encrypting the seed: " + err.Error())
 		}
-		seed = encryptedSeed
 	}
-	err = WriteSeed(user, protection, seed)
-	ClearByteSlice(seed)
+	err = WriteSeed(seedFile)
+	ClearByteSlice(seedFile.Seed)
 	if err != nil {
-		return errors.New("Error writing seed to file: " + err.Error())
+		return nil, errors.New("Error writing seed to file: " + err.Error())
 	}
-	return nil
+	return seedFile, nil
 }
 
 func DateIsValid(date *[]byte) bool {
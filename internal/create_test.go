@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// Cheap Argon2ID parameters to keep the tests fast
+var testPassphraseArgonParams = ArgonParamsType{1, 64, 1}
+
+func TestEncryptDecryptSeed(t *testing.T) {
+	cases := []struct {
+		tamper func(seedFile *SeedFileType)
+		err    error
+	}{
+		{
+			func(seedFile *SeedFileType) {},
+			nil,
+		},
+		{
+			func(seedFile *SeedFileType) { seedFile.DefaultUser = "b@b" },
+			errors.New("Authentication failed, the passphrase is wrong or the seed file was modified"),
+		},
+		{
+			func(seedFile *SeedFileType) { (*seedFile.Seed)[20]++ },
+			errors.New("Authentication failed, the passphrase is wrong or the seed file was modified"),
+		},
+	}
+	for _, c := range cases {
+		seed := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+		seedFile := NewSeedFile("a@a", nil)
+		seedFile.PassphraseArgon = testPassphraseArgonParams
+		passphrase := []byte("passphrase")
+		err := EncryptSeed(seedFile, &[]byte{17, 5, 2, 85, 178, 255, 0, 29}, &passphrase, nil)
+		if err != nil {
+			t.Errorf("EncryptSeed() - %s", err)
+			continue
+		}
+		if seedFile.Cipher != seedCipherAESGCM || len(seedFile.PassphraseSalt) != 16 {
+			t.Errorf("EncryptSeed() produced cipher %s and salt %v", seedFile.Cipher, seedFile.PassphraseSalt)
+		}
+		content := seedFile.serialize()
+		seedFile, err = parseSeed(content)
+		if err != nil {
+			t.Errorf("parseSeed() - %s", err)
+			continue
+		}
+		c.tamper(seedFile)
+		passphrase = []byte("passphrase")
+		out, err := DecryptSeed(seedFile, &passphrase, nil)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("DecryptSeed() - %s", m)
+		}
+		if err == nil && !reflect.DeepEqual(*out, seed) {
+			t.Errorf("DecryptSeed() == %v want %v", *out, seed)
+		}
+	}
+}
+
+func TestUpgradeSeed(t *testing.T) {
+	seed := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+	passphrase := []byte("passphrase")
+	legacySeed := append([]byte{}, seed...)
+	Checksumize(&legacySeed)
+	encryptedSeed, err := EncryptAES(&legacySeed, MakeKey(&passphrase, testPassphraseArgonParams, []byte{}), io.ReadFull)
+	if err != nil {
+		t.Fatalf("EncryptAES() - %s", err)
+	}
+	seedFile := &SeedFileType{
+		Version:         1,
+		DefaultUser:     "a@a",
+		Protection:      seedProtectionPassphrase,
+		Cipher:          seedCipherAESCFB,
+		SeedSalt:        seedSaltBirthdate,
+		SeedArgon:       legacySeedArgonParams,
+		PassphraseArgon: testPassphraseArgonParams,
+		PassphraseSalt:  []byte{},
+		Seed:            encryptedSeed,
+	}
+	if !seedFile.NeedsUpgrade() {
+		t.Errorf("NeedsUpgrade() == false for a legacy seed file")
+	}
+	passphrase = []byte("wrong")
+	err = UpgradeSeed(seedFile, &passphrase, nil)
+	equal, m := errorsEqual(err, errors.New("Checksum verification failed"))
+	if !equal {
+		t.Errorf("UpgradeSeed() with a wrong passphrase - %s", m)
+	}
+	passphrase = []byte("passphrase")
+	err = UpgradeSeed(seedFile, &passphrase, nil)
+	if err != nil {
+		t.Fatalf("UpgradeSeed() - %s", err)
+	}
+	if seedFile.NeedsUpgrade() || seedFile.Cipher != seedCipherAESGCM || seedFile.PassphraseArgon != DefaultPassphraseArgonParams {
+		t.Errorf("UpgradeSeed() produced %v", seedFile)
+	}
+	passphrase = []byte("passphrase")
+	out, err := DecryptSeed(seedFile, &passphrase, nil)
+	if err != nil {
+		t.Fatalf("DecryptSeed() - %s", err)
+	}
+	if !reflect.DeepEqual(*out, seed) {
+		t.Errorf("DecryptSeed() == %v want %v", *out, seed)
+	}
+}
+
+func TestEncryptDecryptSeedKeyfile(t *testing.T) {
+	keyfileSHA3 := &[32]byte{1, 2, 3}
+	wrongKeyfileSHA3 := &[32]byte{1, 2, 4}
+	cases := []struct {
+		passphrase        []byte
+		keyfileSHA3       *[32]byte
+		protection        string
+		decryptPassphrase []byte
+		decryptKeyfile    *[32]byte
+		err               error
+	}{
+		{nil, keyfileSHA3, seedProtectionKeyfile, nil, keyfileSHA3, nil},
+		{nil, keyfileSHA3, seedProtectionKeyfile, []byte("ignored"), keyfileSHA3, nil},
+		{nil, keyfileSHA3, seedProtectionKeyfile, nil, wrongKeyfileSHA3, errors.New("Authentication failed, the passphrase is wrong or the seed file was modified")},
+		{nil, keyfileSHA3, seedProtectionKeyfile, nil, nil, errors.New("A keyfile is required to decrypt the seed")},
+		{[]byte("passphrase"), keyfileSHA3, seedProtectionPassphraseKeyfile, []byte("passphrase"), keyfileSHA3, nil},
+		{[]byte("passphrase"), keyfileSHA3, seedProtectionPassphraseKeyfile, []byte("passphrase"), nil, errors.New("A keyfile is required to decrypt the seed")},
+		{[]byte("passphrase"), keyfileSHA3, seedProtectionPassphraseKeyfile, nil, keyfileSHA3, errors.New("A passphrase is required to decrypt the seed")},
+		{[]byte("passphrase"), keyfileSHA3, seedProtectionPassphraseKeyfile, []byte("wrong"), keyfileSHA3, errors.New("Authentication failed, the passphrase is wrong or the seed file was modified")},
+	}
+	for _, c := range cases {
+		seed := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+		seedFile := NewSeedFile("a@a", nil)
+		seedFile.PassphraseArgon = testPassphraseArgonParams
+		var passphrase *[]byte
+		if c.passphrase != nil {
+			passphrase = &[]byte{}
+			*passphrase = append(*passphrase, c.passphrase...)
+		}
+		err := EncryptSeed(seedFile, &[]byte{17, 5, 2, 85, 178, 255, 0, 29}, passphrase, c.keyfileSHA3)
+		if err != nil {
+			t.Errorf("EncryptSeed() - %s", err)
+			continue
+		}
+		seedFile, err = parseSeed(seedFile.serialize())
+		if err != nil {
+			t.Errorf("parseSeed() - %s", err)
+			continue
+		}
+		if seedFile.Protection != c.protection || !seedFile.RequiresKeyfile() || seedFile.RequiresPassphrase() != (c.passphrase != nil) {
+			t.Errorf("EncryptSeed() produced protection %s want %s", seedFile.Protection, c.protection)
+		}
+		passphrase = nil
+		if c.decryptPassphrase != nil {
+			passphrase = &[]byte{}
+			*passphrase = append(*passphrase, c.decryptPassphrase...)
+		}
+		out, err := DecryptSeed(seedFile, passphrase, c.decryptKeyfile)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("DecryptSeed() - %s", m)
+		}
+		if err == nil && !reflect.DeepEqual(*out, seed) {
+			t.Errorf("DecryptSeed() == %v want %v", *out, seed)
+		}
+	}
+	err := EncryptSeed(NewSeedFile("a@a", nil), &[]byte{1}, nil, nil)
+	equal, m := errorsEqual(err, errors.New("A passphrase or a keyfile is needed to protect the seed"))
+	if !equal {
+		t.Errorf("EncryptSeed() without any factor - %s", m)
+	}
+}
@@ -44,3 +44,46 @@ func DecryptAES(ciphertext *[]byte, key *[32]byte) (plaintext *[]byte, err error
 	stream.XORKeyStream(*plaintext, *plaintext)
 	return plaintext, nil
 }
+
+// EncryptAESGCM encrypts and authenticates the plaintext together with the additional data,
+// the nonce being prepended to the ciphertext
+func EncryptAESGCM(plaintext *[]byte, key *[32]byte, additionalData []byte, ioReadFull ioReadFullFunc) (ciphertext *[]byte, err error) {
+	block, err := aes.NewCipher((*key)[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = ioReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext = new([]byte)
+	*ciphertext = aead.Seal(nonce, nonce, *plaintext, additionalData)
+	return ciphertext, nil
+}
+
+// DecryptAESGCM decrypts the ciphertext and verifies its authenticity together with the additional data
+func DecryptAESGCM(ciphertext *[]byte, key *[32]byte, additionalData []byte) (plaintext *[]byte, err error) {
+	block, err := aes.NewCipher((*key)[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(*ciphertext) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("Invalid cipher size which should be bigger than nonce and tag sizes")
+	}
+	nonce := (*ciphertext)[:aead.NonceSize()]
+	plaintext = new([]byte)
+	*plaintext, err = aead.Open(nil, nonce, (*ciphertext)[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, errors.New("Authentication failed, the passphrase is wrong or the seed file was modified")
+	}
+	return plaintext, nil
+}
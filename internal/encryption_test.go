@@ -115,3 +115,98 @@ func TestEncryptDecryptAES(t *testing.T) {
 		}
 	}
 }
+
+func TestEncryptAESGCM(t *testing.T) {
+	cases := []struct {
+		ioReadFull     func(reader io.Reader, nonce []byte) (n int, err error)
+		plaintext      []byte
+		key            [32]byte
+		additionalData []byte
+		ciphertext     []byte
+		err            error
+	}{
+		{
+			func(reader io.Reader, nonce []byte) (n int, err error) { return 0, nil },
+			[]byte("Short"),
+			[32]byte{77, 249, 176, 89, 67, 8, 215, 248, 198, 94, 153, 202, 42, 202, 34, 10, 208, 251, 232, 58, 82, 34, 65, 47, 213, 83, 141, 76, 199, 18, 103, 133},
+			[]byte("header"),
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 37, 131, 133, 12, 196, 79, 13, 22, 73, 153, 225, 116, 209, 239, 110, 120, 244, 74, 191, 177, 44},
+			nil,
+		},
+		{
+			func(reader io.Reader, nonce []byte) (n int, err error) { return 0, nil },
+			[]byte("Short"),
+			[32]byte{77, 249, 176, 89, 67, 8, 215, 248, 198, 94, 153, 202, 42, 202, 34, 10, 208, 251, 232, 58, 82, 34, 65, 47, 213, 83, 141, 76, 199, 18, 103, 133},
+			nil,
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 37, 131, 133, 12, 196, 248, 72, 100, 225, 1, 21, 250, 88, 179, 181, 212, 80, 237, 230, 246, 235},
+			nil,
+		},
+		{
+			func(reader io.Reader, nonce []byte) (n int, err error) { return 0, errors.New("unexpected EOF") },
+			[]byte("Short"),
+			[32]byte{77, 249, 176, 89, 67, 8, 215, 248, 198, 94, 153, 202, 42, 202, 34, 10, 208, 251, 232, 58, 82, 34, 65, 47, 213, 83, 141, 76, 199, 18, 103, 133},
+			nil,
+			nil,
+			errors.New("unexpected EOF"),
+		},
+	}
+	for _, c := range cases {
+		out, err := EncryptAESGCM(&c.plaintext, &c.key, c.additionalData, c.ioReadFull)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("EncryptAESGCM(%v, %v, %v) - %s", c.plaintext, c.key, c.additionalData, m)
+		}
+		if err == nil && !reflect.DeepEqual(*out, c.ciphertext) {
+			t.Errorf("EncryptAESGCM(%v, %v, %v) == %v want %v", c.plaintext, c.key, c.additionalData, *out, c.ciphertext)
+		}
+	}
+}
+
+func TestDecryptAESGCM(t *testing.T) {
+	cases := []struct {
+		ciphertext     []byte
+		key            [32]byte
+		additionalData []byte
+		plaintext      []byte
+		err            error
+	}{
+		{
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 37, 131, 133, 12, 196, 79, 13, 22, 73, 153, 225, 116, 209, 239, 110, 120, 244, 74, 191, 177, 44},
+			[32]byte{77, 249, 176, 89, 67, 8, 215, 248, 198, 94, 153, 202, 42, 202, 34, 10, 208, 251, 232, 58, 82, 34, 65, 47, 213, 83, 141, 76, 199, 18, 103, 133},
+			[]byte("header"),
+			[]byte("Short"),
+			nil,
+		},
+		{
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 37, 131, 133, 12, 196, 79, 13, 22, 73, 153, 225, 116, 209, 239, 110, 120, 244, 74, 191, 177, 44},
+			[32]byte{77, 249, 176, 89, 67, 8, 215, 248, 198, 94, 153, 202, 42, 202, 34, 10, 208, 251, 232, 58, 82, 34, 65, 47, 213, 83, 141, 76, 199, 18, 103, 133},
+			[]byte("tampered header"),
+			nil,
+			errors.New("Authentication failed, the passphrase is wrong or the seed file was modified"),
+		},
+		{
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 38, 131, 133, 12, 196, 79, 13, 22, 73, 153, 225, 116, 209, 239, 110, 120, 244, 74, 191, 177, 44},
+			[32]byte{77, 249, 176, 89, 67, 8, 215, 248, 198, 94, 153, 202, 42, 202, 34, 10, 208, 251, 232, 58, 82, 34, 65, 47, 213, 83, 141, 76, 199, 18, 103, 133},
+			[]byte("header"),
+			nil,
+			errors.New("Authentication failed, the passphrase is wrong or the seed file was modified"),
+		},
+		{
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 37, 131, 133},
+			[32]byte{77, 249, 176, 89, 67, 8, 215, 248, 198, 94, 153, 202, 42, 202, 34, 10, 208, 251, 232, 58, 82, 34, 65, 47, 213, 83, 141, 76, 199, 18, 103, 133},
+			nil,
+			nil,
+			errors.New("Invalid cipher size which should be bigger than nonce and tag sizes"),
+		},
+	}
+	for _, c := range cases {
+		out, err := DecryptAESGCM(&c.ciphertext, &c.key, c.additionalData)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("DecryptAESGCM(%v, %v, %v) - %s", c.ciphertext, c.key, c.additionalData, m)
+		}
+		if err == nil && !reflect.DeepEqual(*out, c.plaintext) {
+			t.Errorf("DecryptAESGCM(%v, %v, %v) == %v want %v", c.ciphertext, c.key, c.additionalData, *out, c.plaintext)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Entropy returns the bits of entropy of the passwords generated with the settings of the identification, the seed being secret
+func (identification *IdentificationType) Entropy() (bits float64, err error) {
+	length := int(identification.PasswordLength)
+	switch identification.PasswordMode {
+	case PasswordModeCharacters:
+		return charactersEntropy(identification, identification.Constraints)
+	case PasswordModeReadable:
+		return charactersEntropy(identification, identification.readableConstraints())
+	case PasswordModeWords:
+		return float64(length) * math.Log2(float64(len(bip39EnglishWords))), nil
+	case PasswordModePin:
+		options, err := ParsePinOptions(identification.ModeOptions)
+		if err != nil {
+			return 0, err
+		}
+		return pinEntropy(length, options.RejectWeak), nil
+	case PasswordModeAlphabet:
+		options, err := ParseAlphabetOptions(identification.ModeOptions)
+		if err != nil {
+			return 0, err
+		}
+		return float64(length) * math.Log2(float64(utf8.RuneCountInString(options.Alphabet))), nil
+	case PasswordModePronounceable:
+		syllable := []float64{
+			math.Log2(float64(2 * len(pronounceableConsonants))),
+			math.Log2(float64(len(pronounceableVowels))),
+			math.Log2(float64(len(pronounceableConsonants))),
+		}
+		for i := 0; i < length; i++ {
+			bits += syllable[i%3]
+		}
+		return bits, nil
+	default:
+		return 0, errors.New("Password mode '" + identification.PasswordMode + "' is not supported by this program, please update it")
+	}
+}
+
+// charactersEntropy returns the entropy of passwords of characters of the version 4, a lower bound with password rules
+// as the positions of the required characters are not counted, the versions 1 to 3 being slightly biased
+func charactersEntropy(identification *IdentificationType, constraints CharacterConstraintsType) (bits float64, err error) {
+	length := int(identification.PasswordLength)
+	if constraints.Required != "" || len(constraints.Counts) > 0 {
+		return constraintsEntropy(length, constraints), nil
+	}
+	if identification.PasswordRules != "" {
+		rules, err := ParsePasswordRules(identification.PasswordRules)
+		if err != nil {
+			return 0, err
+		}
+		unallowedCharacters := BuildUnallowedCharacters(constraints)
+		unallowed := unallowedCharacters.Serialize()
+		allowed := characterSetWithout(rules.Allowed, unallowed)
+		for _, set := range rules.Required {
+			bits += math.Log2(float64(len(characterSetWithout(set, unallowed))))
+		}
+		return bits + float64(length-len(rules.Required))*math.Log2(float64(len(allowed))), nil
+	}
+	allowedCharacters, asciiOrder := allowedCharactersV4(identification.PasswordLength, constraints)
+	if len(asciiOrder) == 0 {
+		return 0, nil
+	}
+	// Distinct orders of the types, times the probability of a letter first if letters are allowed
+	counts := make(map[asciiType]int)
+	for _, t := range asciiOrder {
+		counts[t]++
+		bits += math.Log2(float64(len(allowedCharacters[t])))
+	}
+	bits += log2Factorial(length)
+	for _, count := range counts {
+		bits -= log2Factorial(count)
+	}
+	letters := counts[asciiLowercase] + counts[asciiUppercase]
+	if length > 1 && letters > 0 {
+		bits += math.Log2(float64(letters) / float64(length))
+	}
+	return bits, nil
+}
+
+// constraintsEntropy estimates the entropy of passwords with required characters or counts of characters,
+// counting the characters drawn for the minimum counts and the other characters drawn from all the allowed
+// characters, ignoring the maximum counts and the positions of the characters
+func constraintsEntropy(length int, constraints CharacterConstraintsType) (bits float64) {
+	allowedCharacters := constraints.allowedCharacters()
+	drawn := len(constraints.Required)
+	all := 0
+	for class, t := range constraintClasses {
+		allowed := len(allowedCharacters[t])
+		all += allowed
+		if missing := constraints.Counts[class].Min - constraints.requiredOfType(t); missing > 0 && allowed > 0 {
+			bits += float64(missing) * math.Log2(float64(allowed))
+			drawn += missing
+		}
+	}
+	if length > drawn && all > 0 {
+		bits += float64(length-drawn) * math.Log2(float64(all))
+	}
+	return bits
+}
+
+func log2Factorial(n int) float64 {
+	lgamma, _ := math.Lgamma(float64(n + 1))
+	return lgamma / math.Ln2
+}
+
+// pinEntropy returns the entropy of codes of uniformly distributed digits, counting the weak codes
+// to reject for codes of up to 5 digits only as counting them takes too long for longer codes,
+// for which it is an upper bound off by less than a bit
+func pinEntropy(length int, rejectWeak bool) float64 {
+	bits := float64(length) * math.Log2(10)
+	if !rejectWeak || length > 5 {
+		return bits
+	}
+	total := int(math.Pow10(length))
+	weak := 0
+	format := "%0" + strconv.Itoa(length) + "d"
+	for i := 0; i < total; i++ {
+		if IsWeakPin(fmt.Sprintf(format, i)) {
+			weak++
+		}
+	}
+	return math.Log2(float64(total - weak))
+}
+
+// FormatEntropy returns the entropy rounded down to the bit, i.e. "128 bits"
+func FormatEntropy(bits float64) string {
+	return strconv.Itoa(int(math.Floor(bits+1e-9))) + " bits"
+}
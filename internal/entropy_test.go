@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIdentificationEntropy(t *testing.T) {
+	cases := []struct {
+		identification IdentificationType
+		bits           float64
+	}{
+		// the types are cycled through from lowercase so a single character is lowercase
+		{IdentificationType{PasswordLength: 1, PasswordDerivationVersion: 4, Constraints: CharacterConstraintsType{Forbidden: "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~0123456789"}}, math.Log2(26)},
+		// lowercase then digit, the only order with a letter first
+		{IdentificationType{PasswordLength: 2, PasswordDerivationVersion: 4, Constraints: CharacterConstraintsType{Forbidden: "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~ABCDEFGHIJKLMNOPQRSTUVWXYZ"}}, math.Log2(26) + math.Log2(10)},
+		// 3!/(2!1!) orders of which 2 start with a letter
+		{IdentificationType{PasswordLength: 3, PasswordDerivationVersion: 4, Constraints: CharacterConstraintsType{Forbidden: "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~ABCDEFGHIJKLMNOPQRSTUVWXYZ"}}, 2*math.Log2(26) + math.Log2(10) + 1},
+		{IdentificationType{PasswordLength: 4, PasswordDerivationVersion: 4, PasswordRules: "required: digit; allowed: lower"}, math.Log2(10) + 3*math.Log2(36)},
+		{IdentificationType{PasswordLength: 6, PasswordMode: PasswordModeWords, ModeOptions: `{"separator":"-","capitalize":false}`}, 66},
+		{IdentificationType{PasswordLength: 4, PasswordMode: PasswordModePin, ModeOptions: `{"reject_weak":false}`}, 4 * math.Log2(10)},
+		{IdentificationType{PasswordLength: 3, PasswordMode: PasswordModePronounceable}, math.Log2(34 * 5 * 17)},
+	}
+	for _, c := range cases {
+		bits, err := c.identification.Entropy()
+		if err != nil {
+			t.Errorf("Entropy(%v) - %s", c.identification, err)
+			continue
+		}
+		if math.Abs(bits-c.bits) > 1e-9 {
+			t.Errorf("Entropy(%v) == %f want %f", c.identification, bits, c.bits)
+		}
+	}
+	readable := IdentificationType{PasswordLength: 20, PasswordDerivationVersion: 4, PasswordMode: PasswordModeReadable}
+	characters := IdentificationType{PasswordLength: 20, PasswordDerivationVersion: 4}
+	readableBits, _ := readable.Entropy()
+	charactersBits, _ := characters.Entropy()
+	if readableBits >= charactersBits || charactersBits < 120 {
+		t.Errorf("Entropy() of default and readable passwords are %f and %f", charactersBits, readableBits)
+	}
+	pin := IdentificationType{PasswordLength: 4, PasswordMode: PasswordModePin, ModeOptions: `{"reject_weak":true}`}
+	bits, _ := pin.Entropy()
+	if bits >= 4*math.Log2(10) || bits < 13 {
+		t.Errorf("Entropy() of 4 digits rejecting weak codes is %f", bits)
+	}
+	_, err := (&IdentificationType{PasswordMode: "emoji"}).Entropy()
+	if err == nil {
+		t.Errorf("Entropy() of an unsupported password mode should fail")
+	}
+}
+
+func TestFormatEntropy(t *testing.T) {
+	cases := map[float64]string{0: "0 bits", 13.99: "13 bits", 66: "66 bits", 65.99999999999: "66 bits"}
+	for bits, expected := range cases {
+		if out := FormatEntropy(bits); out != expected {
+			t.Errorf("FormatEntropy(%f) == %q want %q", bits, out, expected)
+		}
+	}
+}
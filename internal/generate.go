@@ -2,14 +2,42 @@ package internal
 
 import (
 	"encoding/binary"
+	"errors"
+	"io"
 	"log"
-	"math/rand"
 	"strings"
 
 	"github.com/techsek/derivatex/constants"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
 )
 
+// Domain separation strings of the password derivation version 4
+const (
+	passwordDerivationV4Salt           = "derivatex password derivation v4"
+	passwordDerivationV4Identification = "identification"
+	passwordDerivationV4Characters     = "characters"
+	passwordDerivationV4Rules          = "password rules"
+)
+
+// lengthPrefixed returns the field preceded by its length as a 4 bytes big endian integer,
+// so that the concatenation of several fields can't be obtained from other fields
+func lengthPrefixed(field string) []byte {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(field)))
+	return append(length[:], []byte(field)...)
+}
+
 func MakePasswordDigest(clientSeed *[]byte, website, user string, passwordDerivationVersion uint16) (passwordDigest *[32]byte) {
+	if passwordDerivationVersion >= 4 {
+		// Key of the identification derived with HKDF-SHA3-256 from the seed
+		info := []byte(passwordDerivationV4Identification)
+		info = append(info, lengthPrefixed(website)...)
+		info = append(info, lengthPrefixed(user)...)
+		passwordDigest = new([32]byte)
+		io.ReadFull(hkdf.New(sha3.New256, *clientSeed, []byte(passwordDerivationV4Salt), info), (*passwordDigest)[:])
+		return passwordDigest
+	}
 	input := new([]byte)
 	*input = append(*clientSeed, []byte(website)...)
 	if passwordDerivationVersion > 1 {
@@ -43,7 +71,35 @@ var (
 
 type unallowedCharactersType map[asciiType]string
 
-func SatisfyPassword(passwordDigest *[32]byte, passwordLength uint8, round uint16, unallowedCharacters unallowedCharactersType, passwordDerivationVersion uint16) string {
+// SatisfyPassword derives a password satisfying the character constraints. Constraints only forbidding characters
+// are supported by all the password derivation versions and derive the same passwords as the unallowed characters
+// of previous versions, the other constraints require the version 4 or above.
+func SatisfyPassword(passwordDigest *[32]byte, passwordLength uint16, round uint16, constraints CharacterConstraintsType, passwordDerivationVersion uint16) (string, error) {
+	if !constraints.OnlyForbids() && passwordDerivationVersion < 4 {
+		return "", errors.New("Allowed or required characters and counts of characters require the password derivation version 4 or above")
+	}
+	if passwordDerivationVersion >= 4 && passwordLength > 0 && !constraints.IsAnythingAllowed() {
+		return "", errors.New("No character is allowed by the character constraints")
+	}
+	if constraints.Required != "" || len(constraints.Counts) > 0 {
+		err := constraints.Validate()
+		if err != nil {
+			return "", err
+		}
+		return satisfyConstraintsV4(passwordDigest, int(passwordLength), round, constraints)
+	}
+	if passwordDerivationVersion >= 4 {
+		return satisfyPasswordV4(passwordDigest, passwordLength, round, constraints), nil
+	}
+	password := satisfyPasswordLegacy(passwordDigest, passwordLength, round, BuildUnallowedCharacters(constraints), passwordDerivationVersion)
+	if password == "" && passwordLength > 0 {
+		return "", errors.New("No character is allowed by the character constraints")
+	}
+	return password, nil
+}
+
+// satisfyPasswordLegacy derives the password of the versions 1 to 3 from the digest and pseudo random generators seeded with it
+func satisfyPasswordLegacy(passwordDigest *[32]byte, passwordLength uint16, round uint16, unallowedCharacters unallowedCharactersType, passwordDerivationVersion uint16) string {
 	// Rounds of password (to renew password, in example)
 	var digestSlicePtr = new([]byte)
 	var k uint16
@@ -56,15 +112,15 @@ func SatisfyPassword(passwordDigest *[32]byte, passwordLength uint8, round uint1
 	// Pseudo Random generator initialization
 	var randInt func() int64
 	if passwordDerivationVersion < 3 {
-		source := rand.NewSource(int64(binary.BigEndian.Uint64(password)))
-		randInt = source.Int63
+		source := newLegacySource(int64(binary.BigEndian.Uint64(password)))
+		randInt = source.randInt63
 	} else {
 		source := newSource(binary.BigEndian.Uint64(password))
 		randInt = source.randInt64
 	}
 
 	// Extends the password using the pseudo random generator, if needed
-	for uint8(len(password)) < passwordLength {
+	for len(password) < int(passwordLength) {
 		password = append(password, byte(randInt()%256))
 	}
 
@@ -121,52 +177,95 @@ func SatisfyPassword(passwordDigest *[32]byte, passwordLength uint8, round uint1
 	return string(password)
 }
 
-func BuildUnallowedCharacters(noSymbol, noDigit, noUppercase, noLowercase bool, excludeCharacters string) (unallowedCharacters unallowedCharactersType) {
+// satisfyPasswordV4 draws the character types and characters of the password uniformly from
+// a HKDF stream of the identification key and the round, using rejection sampling
+func satisfyPasswordV4(passwordDigest *[32]byte, passwordLength uint16, round uint16, constraints CharacterConstraintsType) string {
+	info := []byte(passwordDerivationV4Characters)
+	info = append(info, byte(round>>8), byte(round))
+	stream := newHKDFStream((*passwordDigest)[:], info)
+	allowedCharacters, asciiOrder := allowedCharactersV4(passwordLength, constraints)
+	if len(asciiOrder) == 0 { // all characters are unallowed
+		return ""
+	}
+	lettersAllowed := len(allowedCharacters[asciiLowercase]) > 0 || len(allowedCharacters[asciiUppercase]) > 0
+	shuffleASCIIOrderUniformly(asciiOrder, stream)
+	// Shuffle again until the first character is a letter, if letters are allowed
+	for len(asciiOrder) > 1 && lettersAllowed && asciiOrder[0] != asciiLowercase && asciiOrder[0] != asciiUppercase {
+		shuffleASCIIOrderUniformly(asciiOrder, stream)
+	}
+	password := make([]byte, passwordLength)
+	for i, t := range asciiOrder {
+		password[i] = allowedCharacters[t][stream.uniformInt(len(allowedCharacters[t]))]
+	}
+	return string(password)
+}
+
+// allowedCharactersV4 returns the allowed characters of each type and the types of the password
+// before shuffling, cycling through the allowed types up to the password length
+func allowedCharactersV4(passwordLength uint16, constraints CharacterConstraintsType) (allowedCharacters map[asciiType][]byte, asciiOrder []asciiType) {
+	allowedCharacters = constraints.allowedCharacters()
+	for _, t := range []asciiType{asciiLowercase, asciiUppercase, asciiDigit, asciiSymbol} {
+		if len(allowedCharacters[t]) > 0 {
+			asciiOrder = append(asciiOrder, t)
+		}
+	}
+	if len(asciiOrder) == 0 {
+		return allowedCharacters, nil
+	}
+	for len(asciiOrder) < int(passwordLength) {
+		asciiOrder = append(asciiOrder, asciiOrder...)
+	}
+	return allowedCharacters, asciiOrder[:passwordLength]
+}
+
+// BuildUnallowedCharacters returns the unallowed characters of each type for the constraints,
+// the forbidden characters and the printable characters not in the allowed characters if any
+func BuildUnallowedCharacters(constraints CharacterConstraintsType) (unallowedCharacters unallowedCharactersType) {
 	unallowedCharacters = make(unallowedCharactersType)
 	unallowedCharacters[asciiSymbol] = ""
 	unallowedCharacters[asciiDigit] = ""
 	unallowedCharacters[asciiUppercase] = ""
 	unallowedCharacters[asciiLowercase] = ""
-	if noSymbol {
-		unallowedCharacters[asciiSymbol] += constants.Symbols
-	}
-	if noDigit {
-		unallowedCharacters[asciiDigit] += constants.Digits
-	}
-	if noUppercase {
-		unallowedCharacters[asciiUppercase] += constants.Uppercases
-	}
-	if noLowercase {
-		unallowedCharacters[asciiLowercase] += constants.Lowercases
+	unallowed := constraints.Forbidden
+	if constraints.Allowed != "" {
+		for b := byte(33); b <= 126; b++ {
+			if strings.IndexByte(constraints.Allowed, b) < 0 {
+				unallowed += string(b)
+			}
+		}
 	}
-	for i := range excludeCharacters {
-		t := byteASCIIType(excludeCharacters[i])
-		if !strings.Contains(unallowedCharacters[t], string(excludeCharacters[i])) {
-			unallowedCharacters[t] += string(excludeCharacters[i])
+	for i := range unallowed {
+		t := byteASCIIType(unallowed[i])
+		if !strings.Contains(unallowedCharacters[t], string(unallowed[i])) {
+			unallowedCharacters[t] += string(unallowed[i])
 		}
 	}
 	return unallowedCharacters
 }
 
-func (unallowedCharacters *unallowedCharactersType) IsAnythingAllowed() bool {
-	if len((*unallowedCharacters)[asciiDigit]) < len(constants.Digits) {
-		return true
-	}
-	if len((*unallowedCharacters)[asciiSymbol]) < len(constants.Symbols) {
-		return true
-	}
-	if len((*unallowedCharacters)[asciiLowercase]) < len(constants.Lowercases) {
-		return true
-	}
-	if len((*unallowedCharacters)[asciiUppercase]) < len(constants.Uppercases) {
-		return true
+// asciiTypeCharacters are the characters unallowed for each type by the flags of BuildUnallowedCharacters
+var asciiTypeCharacters = map[asciiType]string{
+	asciiLowercase: constants.Lowercases,
+	asciiUppercase: constants.Uppercases,
+	asciiDigit:     constants.Digits,
+	asciiSymbol:    constants.Symbols,
+}
+
+// isTypeUnallowed returns true if all the characters of the type flag are unallowed,
+// as constants.Uppercases does not contain all the uppercase letters
+func (unallowedCharacters unallowedCharactersType) isTypeUnallowed(t asciiType) bool {
+	for _, c := range asciiTypeCharacters[t] {
+		if !strings.ContainsRune(unallowedCharacters[t], c) {
+			return false
+		}
 	}
-	return false
+	return true
 }
 
+// Serialize returns all the unallowed characters, in the order of the types
 func (unallowedCharacters *unallowedCharactersType) Serialize() (s string) {
-	for k := range *unallowedCharacters {
-		s += (*unallowedCharacters)[k]
+	for _, t := range []asciiType{asciiLowercase, asciiUppercase, asciiDigit, asciiSymbol, asciiOther} {
+		s += (*unallowedCharacters)[t]
 	}
 	return s
 }
@@ -196,6 +295,14 @@ func byteASCIIType(b byte) asciiType {
 	return asciiOther
 }
 
+// shuffleASCIIOrderUniformly is the Fisher-Yates shuffle with uniformly distributed indexes
+func shuffleASCIIOrderUniformly(asciiOrder []asciiType, stream *hkdfStream) {
+	for i := len(asciiOrder) - 1; i > 0; i-- {
+		j := stream.uniformInt(i + 1)
+		asciiOrder[i], asciiOrder[j] = asciiOrder[j], asciiOrder[i]
+	}
+}
+
 func shuffleASCIIOrder(asciiOrder *[]asciiType, randInt func() int64) {
 	var i, j int
 	for i = len(*asciiOrder) - 1; i > 0; i-- {
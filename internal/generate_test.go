@@ -164,9 +164,9 @@ func Test_shuffleAsciiOrder(t *testing.T) {
 func Test_SatisfyPassword(t *testing.T) {
 	cases := []struct {
 		passwordDigest           [32]byte
-		passwordLength           uint8
+		passwordLength           uint16
 		round                    uint16
-		unallowedCharacters      unallowedCharactersType
+		constraints              CharacterConstraintsType
 		programDerivationVersion uint16
 		password                 string
 	}{
@@ -174,7 +174,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{173, 201, 139, 108, 102, 233, 47, 218, 121, 176, 142, 54, 143, 21, 28, 204, 115, 253, 79, 230, 151, 171, 237, 46, 77, 152, 189, 34, 89, 28, 149, 29},
 			0,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			``,
 		},
@@ -182,7 +182,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{173, 201, 139, 108, 102, 233, 47, 218, 121, 176, 142, 54, 143, 21, 28, 204, 115, 253, 79, 230, 151, 171, 237, 46, 77, 152, 189, 34, 89, 28, 149, 29},
 			2,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			`Fq`,
 		},
@@ -190,7 +190,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{173, 201, 139, 108, 102, 233, 47, 218, 121, 176, 142, 54, 143, 21, 28, 204, 115, 253, 79, 230, 151, 171, 237, 46, 77, 152, 189, 34, 89, 28, 149, 29},
 			4,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			`jA9;`,
 		},
@@ -198,7 +198,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{173, 201, 139, 108, 102, 233, 47, 218, 121, 176, 142, 54, 143, 21, 28, 204, 115, 253, 79, 230, 151, 171, 237, 46, 77, 152, 189, 34, 89, 28, 149, 29},
 			10,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			`U1b5&O/Zyu`,
 		},
@@ -206,7 +206,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{47, 245, 150, 209, 165, 136, 114, 40, 237, 204, 217, 246, 1, 3, 162, 139, 129, 118, 158, 112, 235, 62, 56, 205, 248, 78, 172, 151, 94, 146, 57, 158},
 			10,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			`Otf}Aw82T*`,
 		},
@@ -214,7 +214,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{47, 245, 150, 209, 165, 136, 114, 40, 237, 204, 217, 246, 1, 3, 162, 139, 129, 118, 158, 112, 235, 62, 56, 205, 248, 78, 172, 151, 94, 146, 57, 158},
 			10,
 			2,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			`L;ggD48X>t`,
 		},
@@ -222,7 +222,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{173, 201, 139, 108, 102, 233, 47, 218, 121, 176, 142, 54, 143, 21, 28, 204, 115, 253, 79, 230, 151, 171, 237, 46, 77, 152, 189, 34, 89, 28, 149, 29},
 			50,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			`zX'l+6WYyx.e&8G8>647sKmn93AV^Jb]d_1Zz3K.-y7Z*j4Z,H`,
 		},
@@ -230,7 +230,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{173, 201, 139, 108, 102, 233, 47, 218, 121, 176, 142, 54, 143, 21, 28, 204, 115, 253, 79, 230, 151, 171, 237, 46, 77, 152, 189, 34, 89, 28, 149, 29},
 			10,
 			1,
-			BuildUnallowedCharacters(false, false, true, true, ""),
+			NewCharacterConstraints(false, false, true, true, ""),
 			1, // 1 == 2
 			`<636/\/)38`,
 		},
@@ -238,7 +238,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{173, 201, 139, 108, 102, 233, 47, 218, 121, 176, 142, 54, 143, 21, 28, 204, 115, 253, 79, 230, 151, 171, 237, 46, 77, 152, 189, 34, 89, 28, 149, 29},
 			10,
 			1,
-			BuildUnallowedCharacters(true, false, false, false, ""),
+			NewCharacterConstraints(true, false, false, false, ""),
 			1, // 1 == 2
 			`j3nlfCL08L`,
 		},
@@ -246,7 +246,7 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{156, 176, 130, 203, 252, 241, 158, 62, 20, 50, 82, 100, 43, 23, 148, 2, 180, 43, 42, 72, 204, 24, 21, 159, 110, 52, 244, 177, 101, 165, 79, 195},
 			50,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			1, // 1 == 2
 			`IF>t8UyMCSRU3Mt0\4*<l=sf5l8Le{n2{'85RO0Iij=$04{d&i`,
 		},
@@ -254,15 +254,87 @@ func Test_SatisfyPassword(t *testing.T) {
 			[32]byte{156, 176, 130, 203, 252, 241, 158, 62, 20, 50, 82, 100, 43, 23, 148, 2, 180, 43, 42, 72, 204, 24, 21, 159, 110, 52, 244, 177, 101, 165, 79, 195},
 			50,
 			1,
-			unallowedCharactersType{},
+			CharacterConstraintsType{},
 			3,
 			`Hw:1'l18=Jud9Z^9x8N34Lynn(S]eGzBD3X[0Ec"#u1]L-3e=Y`,
 		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			0,
+			1,
+			CharacterConstraintsType{},
+			4,
+			``,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			1,
+			1,
+			CharacterConstraintsType{},
+			4,
+			`i`,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			4,
+			1,
+			CharacterConstraintsType{},
+			4,
+			`cE/1`,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			20,
+			1,
+			CharacterConstraintsType{},
+			4,
+			`cJ568'[bEw)9/Tc3|UYw`,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			20,
+			2,
+			CharacterConstraintsType{},
+			4,
+			`ak?*9y'74BVP*1ZdJ$9r`,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			50,
+			1,
+			CharacterConstraintsType{},
+			4,
+			`G#V&0AC-a9z2L#p0Ke5pq_7bN2v0J5+c'SBEw)` + "`" + `ct2H|=2W.8u`,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			10,
+			1,
+			NewCharacterConstraints(false, false, true, true, ""),
+			4,
+			`603>+5\!|2`,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			10,
+			1,
+			NewCharacterConstraints(true, false, false, false, ""),
+			4,
+			`k0xXC5li3Q`,
+		},
+		{
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+			12,
+			1,
+			NewCharacterConstraints(true, true, false, false, "aeiouAEIOU"),
+			4,
+			`dXFmMPmnCWhv`,
+		},
 	}
 	for _, c := range cases {
-		out := SatisfyPassword(&c.passwordDigest, c.passwordLength, c.round, c.unallowedCharacters, c.programDerivationVersion)
-		if out != c.password {
-			t.Errorf("SatisfyPassword(%v, %d, %d, %v, %d) == %s want %s", c.passwordDigest, c.passwordLength, c.round, c.unallowedCharacters, c.programDerivationVersion, out, c.password)
+		out, err := SatisfyPassword(&c.passwordDigest, c.passwordLength, c.round, c.constraints, c.programDerivationVersion)
+		if err != nil || out != c.password {
+			t.Errorf("SatisfyPassword(%v, %d, %d, %v, %d) == %s, %v want %s", c.passwordDigest, c.passwordLength, c.round, c.constraints, c.programDerivationVersion, out, err, c.password)
 		}
 	}
 }
@@ -324,6 +396,41 @@ func Test_MakePasswordDigest(t *testing.T) {
 			2,
 			[32]byte{19, 141, 208, 165, 180, 187, 96, 201, 120, 246, 142, 94, 119, 37, 29, 252, 248, 226, 67, 158, 124, 58, 207, 193, 207, 225, 155, 97, 39, 104, 20, 182},
 		},
+		{
+			[]byte{17, 5, 2, 85, 178, 255, 0, 29},
+			"google",
+			"",
+			4,
+			[32]byte{0x2f, 0x62, 0xd9, 0xc5, 0xc3, 0x20, 0xb6, 0x28, 0xc9, 0x14, 0xe5, 0xb6, 0x31, 0x99, 0xa, 0xa, 0x1d, 0xf4, 0xb3, 0x6d, 0xaf, 0xd6, 0x63, 0x22, 0x52, 0xcf, 0x2a, 0xab, 0x18, 0x4e, 0xb7, 0xcd},
+		},
+		{
+			[]byte{17, 5, 2, 85, 178, 255, 0, 29},
+			"google",
+			"a@a",
+			4,
+			[32]byte{0xe0, 0xe7, 0xde, 0x47, 0xe8, 0x2e, 0x53, 0x1c, 0xe7, 0x4a, 0xa1, 0xb7, 0x40, 0xb5, 0xd0, 0x7d, 0xb2, 0x97, 0x5, 0x77, 0xe3, 0x80, 0xc7, 0x36, 0x1b, 0xf, 0x73, 0x2e, 0x11, 0x69, 0x70, 0x29},
+		},
+		{
+			[]byte{17, 5, 2, 85, 178, 255, 0, 29},
+			"ab",
+			"c",
+			4,
+			[32]byte{0xe5, 0x80, 0x41, 0xb3, 0xe5, 0x42, 0x2c, 0x7c, 0x27, 0x9c, 0xca, 0x18, 0xb7, 0x94, 0x10, 0xf7, 0xb, 0x80, 0x7f, 0xf9, 0xd1, 0x2b, 0x4f, 0x63, 0x66, 0x9e, 0x24, 0xdb, 0x90, 0x6d, 0x4c, 0xd8},
+		},
+		{
+			[]byte{17, 5, 2, 85, 178, 255, 0, 29},
+			"a",
+			"bc",
+			4,
+			[32]byte{0xb6, 0x15, 0xc0, 0x17, 0x32, 0xc4, 0x8e, 0xef, 0x9e, 0x6b, 0xfb, 0xa0, 0x85, 0xe4, 0x89, 0x45, 0xf8, 0xbd, 0x2a, 0x24, 0xfe, 0x1f, 0x0, 0xe0, 0xfe, 0xec, 0xe8, 0xc, 0x2d, 0xa1, 0xac, 0xb7},
+		},
+		{
+			[]byte{17, 5, 2, 85, 178, 255, 0, 29},
+			"facebook",
+			"b@a",
+			4,
+			[32]byte{0xd7, 0xd3, 0xec, 0xbc, 0xe7, 0xd, 0xe1, 0x13, 0xec, 0x59, 0x84, 0x3, 0x87, 0x33, 0x9b, 0x97, 0xab, 0xf1, 0xf9, 0x49, 0x4a, 0x62, 0xba, 0xcf, 0xab, 0x3f, 0x99, 0x8a, 0xea, 0x9e, 0x27, 0x51},
+		},
 	}
 	for _, c := range cases {
 		out := MakePasswordDigest(&c.clientSeed, c.website, c.user, c.passwordDerivationVersion)
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"io/ioutil"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -19,12 +18,11 @@ import (
 var database *sql.DB
 
 func InitiateDatabaseIfNeeded() (err error) {
-	ex, err := os.Executable()
+	path, err := vaultPath(constants.DatabaseFilename)
 	if err != nil {
 		return err
 	}
-	dir := filepath.Dir(ex)
-	database, err = sql.Open("sqlite3", dir+"/"+constants.DatabaseFilename)
+	database, err = sql.Open("sqlite3", path)
 	if err != nil {
 		return err
 	}
@@ -38,22 +36,131 @@ func InitiateDatabaseIfNeeded() (err error) {
 	if err != nil {
 		return err
 	}
+	for _, column := range []string{"password_rules", "password_mode", "mode_options", "character_constraints", "otp_type", "otp_options", "otp_secret"} {
+		err = addColumnIfNeeded(column, "TEXT NOT NULL DEFAULT ''")
+		if err != nil {
+			return err
+		}
+	}
+	err = migrateUnallowedCharacters()
+	if err != nil {
+		return err
+	}
+	err = createKeysTableIfNeeded()
+	if err != nil {
+		return err
+	}
+	return createSiteRulesTableIfNeeded()
+}
+
+// migrateUnallowedCharacters sets the character constraints of the identifications saved by older versions
+// from their unallowed characters, which were serialized in a random order of the character types
+func migrateUnallowedCharacters() (err error) {
+	rows, err := database.Query("SELECT DISTINCT unallowed_characters FROM identifications WHERE character_constraints = '' AND unallowed_characters != ''")
+	if err != nil {
+		return err
+	}
+	var unallowedCharacters []string
+	for rows.Next() {
+		var unallowed string
+		err = rows.Scan(&unallowed)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		unallowedCharacters = append(unallowedCharacters, unallowed)
+	}
+	rows.Close()
+	for _, unallowed := range unallowedCharacters {
+		constraints := NewCharacterConstraints(false, false, false, false, unallowed)
+		_, err = database.Exec("UPDATE identifications SET character_constraints = ? WHERE character_constraints = '' AND unallowed_characters = ?", constraints.String(), unallowed)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// addColumnIfNeeded adds the column to the identifications table of databases created by older versions
+func addColumnIfNeeded(column string, definition string) (err error) {
+	rows, err := database.Query("PRAGMA table_info(identifications)")
+	if err != nil {
+		return err
+	}
+	exists := false
+	for rows.Next() {
+		var cid, notNull, primaryKey int
+		var name, columnType string
+		var defaultValue sql.NullString
+		err = rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		if name == column {
+			exists = true
+		}
+	}
+	rows.Close()
+	if exists {
+		return nil
+	}
+	_, err = database.Exec("ALTER TABLE identifications ADD COLUMN " + column + " " + definition)
+	return err
+}
+
+// identificationColumns are the columns of the identifications table in the order of scanIdentification
+const identificationColumns = "website, user, password_length, round, unallowed_characters, creation_time, program_version, note, password_rules, password_mode, mode_options, character_constraints, otp_type, otp_options, otp_secret"
+
+func scanIdentification(rows *sql.Rows) (identification IdentificationType, err error) {
+	var unallowedCharacters, constraints string
+	err = rows.Scan(
+		&identification.Website,
+		&identification.User,
+		&identification.PasswordLength,
+		&identification.Round,
+		&unallowedCharacters,
+		&identification.CreationTime,
+		&identification.PasswordDerivationVersion,
+		&identification.Note,
+		&identification.PasswordRules,
+		&identification.PasswordMode,
+		&identification.ModeOptions,
+		&constraints,
+		&identification.OTPType,
+		&identification.OTPOptions,
+		&identification.OTPSecret,
+	)
+	if err != nil {
+		return identification, err
+	}
+	if constraints == "" { // saved by an older version after the migration
+		identification.Constraints = NewCharacterConstraints(false, false, false, false, unallowedCharacters)
+		return identification, nil
+	}
+	identification.Constraints, err = ParseCharacterConstraints(constraints)
+	return identification, err
+}
+
 type IdentificationType struct {
 	Website                   string
 	User                      string
-	PasswordLength            uint8 // max 255 otherwise it's ridiculous
+	PasswordLength            uint16 // characters, words or digits, up to 255 for the versions 1 to 3
 	Round                     uint16
-	UnallowedCharacters       string
+	Constraints               CharacterConstraintsType
 	CreationTime              int64
 	PasswordDerivationVersion uint16
 	Note                      string
+	PasswordRules             string // canonical passwordrules, empty if not set
+	PasswordMode              string // PasswordModeCharacters by default
+	ModeOptions               string // options of the password mode, i.e. WordsOptionsType
+	OTPType                   string // OTPTypeNone by default
+	OTPOptions                string // OTPOptionsType
+	OTPSecret                 string // encrypted imported secret, empty if the secret is derived from the seed
 }
 
 func IdentificationTypeLegendStrings() []string {
-	return []string{"Website", "User", "Password Length", "Round", "Unallowed characters", "Age", "Program version", "Note"}
+	return []string{"Website", "User", "Password Length", "Round", "Character constraints", "Age", "Program version", "Note", "Password rules", "Password mode", "Entropy", "OTP"}
 }
 
 func durationString(t time.Time) (durationStr string) {
@@ -75,33 +182,51 @@ func (identification *IdentificationType) ToStrings() []string {
 		identification.User,
 		strconv.FormatUint(uint64(identification.PasswordLength), 10),
 		strconv.FormatUint(uint64(identification.Round), 10),
-		identification.UnallowedCharacters,
+		identification.Constraints.Description(),
 		durationString(time.Unix(identification.CreationTime, 0)),
 		strconv.FormatUint(uint64(identification.PasswordDerivationVersion), 10),
 		identification.Note,
+		identification.PasswordRules,
+		identification.PasswordModeString(),
+		identification.entropyString(),
+		identification.OTPTypeString(),
 	}
 }
 
+// entropyString returns the entropy of the identification for display, '?' if its settings are not supported
+func (identification *IdentificationType) entropyString() string {
+	bits, err := identification.Entropy()
+	if err != nil {
+		return "?"
+	}
+	return FormatEntropy(bits)
+}
+
 func (identification *IdentificationType) GenerationParamsEqualTo(other *IdentificationType) bool {
 	return identification.Website == other.Website &&
 		identification.User == other.User &&
 		identification.PasswordLength == other.PasswordLength &&
 		identification.Round == other.Round &&
-		identification.UnallowedCharacters == other.UnallowedCharacters &&
-		identification.PasswordDerivationVersion == other.PasswordDerivationVersion
+		identification.Constraints.String() == other.Constraints.String() &&
+		identification.PasswordDerivationVersion == other.PasswordDerivationVersion &&
+		identification.PasswordRules == other.PasswordRules &&
+		identification.PasswordMode == other.PasswordMode &&
+		identification.ModeOptions == other.ModeOptions
 }
 
 func (identification *IdentificationType) HasDefaultParams(userIsDefault bool) bool {
 	return userIsDefault &&
 		identification.PasswordLength == constants.DefaultPasswordLength &&
 		identification.Round == 1 &&
-		identification.UnallowedCharacters == "" &&
+		identification.Constraints.IsEmpty() &&
 		identification.PasswordDerivationVersion == constants.PasswordDerivationVersion &&
-		identification.Note == ""
+		identification.Note == "" &&
+		identification.PasswordRules == "" &&
+		identification.PasswordMode == PasswordModeCharacters
 }
 
 func FindIdentificationsByWebsite(website string) (identifications []IdentificationType, err error) {
-	statement, err := database.Prepare("SELECT * FROM identifications WHERE website = ?")
+	statement, err := database.Prepare("SELECT " + identificationColumns + " FROM identifications WHERE website = ?")
 	if err != nil {
 		return nil, err
 	}
@@ -112,16 +237,7 @@ func FindIdentificationsByWebsite(website string) (identifications []Identificat
 	defer rows.Close()
 	var identification IdentificationType
 	for rows.Next() {
-		err = rows.Scan(
-			&identification.Website,
-			&identification.User,
-			&identification.PasswordLength,
-			&identification.Round,
-			&identification.UnallowedCharacters,
-			&identification.CreationTime,
-			&identification.PasswordDerivationVersion,
-			&identification.Note,
-		)
+		identification, err = scanIdentification(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -131,7 +247,7 @@ func FindIdentificationsByWebsite(website string) (identifications []Identificat
 }
 
 func FindIdentification(website string, user string) (identification IdentificationType, err error) {
-	statement, err := database.Prepare("SELECT * FROM identifications WHERE website = ? AND user = ?")
+	statement, err := database.Prepare("SELECT " + identificationColumns + " FROM identifications WHERE website = ? AND user = ?")
 	if err != nil {
 		return identification, err
 	}
@@ -141,16 +257,7 @@ func FindIdentification(website string, user string) (identification Identificat
 	}
 	defer rows.Close()
 	if rows.Next() {
-		err = rows.Scan(
-			&identification.Website,
-			&identification.User,
-			&identification.PasswordLength,
-			&identification.Round,
-			&identification.UnallowedCharacters,
-			&identification.CreationTime,
-			&identification.PasswordDerivationVersion,
-			&identification.Note,
-		)
+		identification, err = scanIdentification(rows)
 		if err != nil {
 			return identification, err
 		}
@@ -159,14 +266,33 @@ func FindIdentification(website string, user string) (identification Identificat
 }
 
 func InsertIdentification(identification IdentificationType) (err error) {
-	statement, err := database.Prepare("INSERT INTO identifications (website, user, password_length, round, unallowed_characters, creation_time, program_version, note) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	statement, err := database.Prepare("INSERT INTO identifications (" + identificationColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	constraints := identification.Constraints.Canonical()
+	// the forbidden characters are also saved as unallowed characters for older versions
+	_, err = statement.Exec(identification.Website, identification.User, identification.PasswordLength, identification.Round, constraints.Forbidden, identification.CreationTime, identification.PasswordDerivationVersion, identification.Note, identification.PasswordRules, identification.PasswordMode, identification.ModeOptions, constraints.String(), identification.OTPType, identification.OTPOptions, identification.OTPSecret)
+	return err
+}
+
+// UpdateIdentificationOTP saves the one time password settings of the identification
+func UpdateIdentificationOTP(identification IdentificationType) (err error) {
+	statement, err := database.Prepare("UPDATE identifications SET otp_type = ?, otp_options = ?, otp_secret = ? WHERE website = ? AND user = ?")
 	if err != nil {
 		return err
 	}
-	_, err = statement.Exec(identification.Website, identification.User, identification.PasswordLength, identification.Round, identification.UnallowedCharacters, identification.CreationTime, identification.PasswordDerivationVersion, identification.Note)
+	_, err = statement.Exec(identification.OTPType, identification.OTPOptions, identification.OTPSecret, identification.Website, identification.User)
 	return err
 }
 
+// KeepOTP sets the one time password settings of the other identification, when replacing it
+func (identification *IdentificationType) KeepOTP(other *IdentificationType) {
+	identification.OTPType = other.OTPType
+	identification.OTPOptions = other.OTPOptions
+	identification.OTPSecret = other.OTPSecret
+}
+
 func SearchIdentifications(query string, searchWebsites, searchUsers bool) (identifications []IdentificationType, err error) {
 	var websiteQuery, userQuery string
 	if searchWebsites {
@@ -175,7 +301,7 @@ func SearchIdentifications(query string, searchWebsites, searchUsers bool) (iden
 	if searchUsers {
 		userQuery = "%" + query + "%"
 	}
-	statement, err := database.Prepare("SELECT * FROM identifications WHERE website LIKE ? OR user LIKE ?")
+	statement, err := database.Prepare("SELECT " + identificationColumns + " FROM identifications WHERE website LIKE ? OR user LIKE ?")
 	if err != nil {
 		return nil, err
 	}
@@ -186,16 +312,7 @@ func SearchIdentifications(query string, searchWebsites, searchUsers bool) (iden
 	defer rows.Close()
 	var identification IdentificationType
 	for rows.Next() {
-		err = rows.Scan(
-			&identification.Website,
-			&identification.User,
-			&identification.PasswordLength,
-			&identification.Round,
-			&identification.UnallowedCharacters,
-			&identification.CreationTime,
-			&identification.PasswordDerivationVersion,
-			&identification.Note,
-		)
+		identification, err = scanIdentification(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -204,13 +321,9 @@ func SearchIdentifications(query string, searchWebsites, searchUsers bool) (iden
 	return identifications, nil
 }
 
+// DumpTable writes the table of the database as CSV to the output file, relative to the working directory
 func DumpTable(tableName string, outputfilename string) error {
-	ex, err := os.Executable()
-	if err != nil {
-		return err
-	}
-	dir := filepath.Dir(ex)
-	rows, err := database.Query("SELECT * FROM " + tableName)
+	rows, err := database.Query("SELECT " + identificationColumns + " FROM " + tableName)
 	if err != nil {
 		return err
 	}
@@ -218,22 +331,13 @@ func DumpTable(tableName string, outputfilename string) error {
 	var identification IdentificationType
 	output := strings.Join(IdentificationTypeLegendStrings(), ",") + "\n"
 	for rows.Next() {
-		err = rows.Scan(
-			&identification.Website,
-			&identification.User,
-			&identification.PasswordLength,
-			&identification.Round,
-			&identification.UnallowedCharacters,
-			&identification.CreationTime,
-			&identification.PasswordDerivationVersion,
-			&identification.Note,
-		)
+		identification, err = scanIdentification(rows)
 		if err != nil {
 			return err
 		}
 		output += strings.Join(identification.ToStrings(), ",") + "\n"
 	}
-	err = ioutil.WriteFile(dir+"/"+outputfilename, []byte(output), 0644)
+	err = ioutil.WriteFile(outputfilename, []byte(output), 0644)
 	return err
 }
 
@@ -249,7 +353,7 @@ func DeleteIdentification(website string, user string) (err error) {
 func GetAllIdentifications(startTime, endTime int64, user string) (identifications []IdentificationType, err error) {
 	var rows *sql.Rows
 	if user == "" {
-		statement, err := database.Prepare("SELECT * FROM identifications WHERE creation_time > ? AND creation_time < ?")
+		statement, err := database.Prepare("SELECT " + identificationColumns + " FROM identifications WHERE creation_time > ? AND creation_time < ?")
 		if err != nil {
 			return nil, err
 		}
@@ -258,7 +362,7 @@ func GetAllIdentifications(startTime, endTime int64, user string) (identificatio
 			return nil, err
 		}
 	} else {
-		statement, err := database.Prepare("SELECT * FROM identifications WHERE creation_time > ? AND creation_time < ? AND user = ?")
+		statement, err := database.Prepare("SELECT " + identificationColumns + " FROM identifications WHERE creation_time > ? AND creation_time < ? AND user = ?")
 		if err != nil {
 			return nil, err
 		}
@@ -270,16 +374,7 @@ func GetAllIdentifications(startTime, endTime int64, user string) (identificatio
 	defer rows.Close()
 	var identification IdentificationType
 	for rows.Next() {
-		err = rows.Scan(
-			&identification.Website,
-			&identification.User,
-			&identification.PasswordLength,
-			&identification.Round,
-			&identification.UnallowedCharacters,
-			&identification.CreationTime,
-			&identification.PasswordDerivationVersion,
-			&identification.Note,
-		)
+		identification, err = scanIdentification(rows)
 		if err != nil {
 			return nil, err
 		}
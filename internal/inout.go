@@ -3,12 +3,13 @@ package internal
 import (
 	"bufio"
 	"bytes"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/fatih/color"
@@ -36,57 +37,110 @@ func ReadSecret(prompt string) (secretPtr *[]byte, err error) {
 	return secretPtr, nil
 }
 
-// We just use sha3 as the input space is already 512 bits and is impossible to crack
-func ReadSeed() (defaultUser string, protection string, seed *[]byte, err error) {
-	ex, err := os.Executable()
+// ReadSecretFrom reads a secret without any prompt from a source which is either
+// env:NAME for an environment variable, unset once read, file:PATH for a file or
+// fd:N for an open file descriptor. A single trailing new line is removed.
+func ReadSecretFrom(source string) (secretPtr *[]byte, err error) {
+	secretPtr = new([]byte)
+	i := strings.Index(source, ":")
+	if i < 0 {
+		return nil, errors.New("Secret source '" + source + "' must be env:NAME, file:PATH or fd:N")
+	}
+	kind, location := source[:i], source[i+1:]
+	switch kind {
+	case "env":
+		value, ok := os.LookupEnv(location)
+		if !ok {
+			return nil, errors.New("Environment variable " + location + " is not set")
+		}
+		*secretPtr = []byte(value) // the string itself can't be cleared
+		os.Unsetenv(location)
+	case "file":
+		*secretPtr, err = ioutil.ReadFile(location)
+	case "fd":
+		var fd uint64
+		fd, err = strconv.ParseUint(location, 10, 32)
+		if err != nil {
+			return nil, errors.New("File descriptor '" + location + "' is not a number")
+		}
+		f := os.NewFile(uintptr(fd), "fd"+location)
+		*secretPtr, err = ioutil.ReadAll(f)
+		f.Close()
+	default:
+		return nil, errors.New("Secret source '" + source + "' must be env:NAME, file:PATH or fd:N")
+	}
 	if err != nil {
-		return "", "", nil, err
+		ClearByteSlice(secretPtr)
+		return nil, err
 	}
-	dir := filepath.Dir(ex)
-	var content = new([]byte)
-	defer ClearByteSlice(content)
-	*content, err = ioutil.ReadFile(dir + "/" + constants.SeedFilename)
+	if bytes.HasSuffix(*secretPtr, []byte("\r\n")) {
+		clearTail(secretPtr, 2)
+	} else if bytes.HasSuffix(*secretPtr, []byte("\n")) {
+		clearTail(secretPtr, 1)
+	}
+	if len(*secretPtr) == 0 {
+		return nil, errors.New("Secret read from " + source + " is empty")
+	}
+	return secretPtr, nil
+}
+
+func seedFilePath() (path string, err error) {
+	return vaultPath(constants.SeedFilename)
+}
+
+// SeedFileExists returns true if the seed file was already created
+func SeedFileExists() (exists bool, err error) {
+	path, err := seedFilePath()
 	if err != nil {
-		return "", "", nil, err
+		return false, err
 	}
+	_, err = os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
 
-	// Reading the file step by step instead of with bytes.Split() to avoid using more memory than necessary for security purposes
-	var i int
-	i = bytes.Index(*content, []byte("Default user: "))
-	if i < 0 {
-		return "", "", nil, errors.New("'Default user: ' not found in " + constants.SeedFilename)
+// We just use sha3 as the input space is already 512 bits and is impossible to crack
+func ReadSeed() (seedFile *SeedFileType, err error) {
+	path, err := seedFilePath()
+	if err != nil {
+		return nil, err
 	}
-	if i > 0 {
-		return "", "", nil, errors.New("'Default user: ' must be the start of " + constants.SeedFilename)
+	var content = new([]byte)
+	*content, err = ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
-	clearAndTrim(content, i+len([]byte("Default user: ")))
-	i = bytes.Index(*content, []byte("\n"))
-	if i < 0 {
-		return "", "", nil, errors.New("New line not found after 'Default user: ' in " + constants.SeedFilename)
+	return parseSeed(content)
+}
+
+// writeFileAtomically writes the content to a temporary file in the same directory
+// and renames it to filename, so that filename is never left half written
+func writeFileAtomically(filename string, content *[]byte, perm os.FileMode) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
 	}
-	defaultUser = string((*content)[:i])
-	clearAndTrim(content, i+len([]byte("\n")))
-	i = bytes.Index(*content, []byte("Protection: "))
-	if i < 0 {
-		return "", "", nil, errors.New("'Protection: ' not found in " + constants.SeedFilename)
+	tmpFilename := f.Name()
+	defer func() {
+		if err != nil {
+			os.Remove(tmpFilename)
+		}
+	}()
+	_, err = f.Write(*content)
+	if err == nil {
+		err = f.Sync()
 	}
-	clearAndTrim(content, i+len([]byte("Protection: ")))
-	i = bytes.Index(*content, []byte("\n"))
-	if i < 0 {
-		return "", "", nil, errors.New("New line not found after 'Protection: ' in " + constants.SeedFilename)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
 	}
-	protection = string((*content)[:i])
-	clearAndTrim(content, i+len([]byte("\n")))
-	i = bytes.Index(*content, []byte("Secret Seed: "))
-	if i < 0 {
-		return "", "", nil, errors.New("'Secret Seed: ' not found in " + constants.SeedFilename)
+	if err != nil {
+		return err
 	}
-	clearAndTrim(content, i+len([]byte("Secret Seed: ")))
-	seed = new([]byte)
-	*seed, err = base64.StdEncoding.DecodeString(string(*content))
-	ClearByteSlice(content)
+	err = os.Chmod(tmpFilename, perm)
 	if err != nil {
-		return "", "", nil, err
+		return err
 	}
-	return defaultUser, protection, seed, nil
+	return os.Rename(tmpFilename, filename)
 }
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_writeFileAtomically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "derivatex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "seed.txt")
+	for _, content := range [][]byte{[]byte("first content"), []byte("second")} {
+		err = writeFileAtomically(filename, &content, 0600)
+		if err != nil {
+			t.Fatalf("writeFileAtomically(%s, %s) - %s", filename, content, err)
+		}
+		out, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, content) {
+			t.Errorf("writeFileAtomically(%s, %s) wrote %s", filename, content, out)
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("writeFileAtomically left %d files in the directory instead of 1", len(files))
+	}
+}
+
+func TestReadSecretFrom(t *testing.T) {
+	dir, err := ioutil.TempDir("", "derivatex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "secret")
+	err = ioutil.WriteFile(filename, []byte("from file\r\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("DERIVATEX_TEST_SECRET", "from environment\n")
+	cases := []struct {
+		source string
+		secret []byte
+		err    error
+	}{
+		{"env:DERIVATEX_TEST_SECRET", []byte("from environment"), nil},
+		{"env:DERIVATEX_TEST_SECRET", nil, errors.New("Environment variable DERIVATEX_TEST_SECRET is not set")},
+		{"file:" + filename, []byte("from file"), nil},
+		{"fd:x", nil, errors.New("File descriptor 'x' is not a number")},
+		{"secret", nil, errors.New("Secret source 'secret' must be env:NAME, file:PATH or fd:N")},
+		{"stdin:0", nil, errors.New("Secret source 'stdin:0' must be env:NAME, file:PATH or fd:N")},
+	}
+	for _, c := range cases {
+		out, err := ReadSecretFrom(c.source)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("ReadSecretFrom(%s) - %s", c.source, m)
+		}
+		if err == nil && !reflect.DeepEqual(*out, c.secret) {
+			t.Errorf("ReadSecretFrom(%s) == %q want %q", c.source, *out, c.secret)
+		}
+	}
+	err = ioutil.WriteFile(filename, []byte("\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadSecretFrom("file:" + filename)
+	equal, m := errorsEqual(err, errors.New("Secret read from file:"+filename+" is empty"))
+	if !equal {
+		t.Errorf("ReadSecretFrom() of an empty file - %s", m)
+	}
+}
@@ -0,0 +1,20 @@
+package internal
+
+import (
+	"errors"
+	"io/ioutil"
+)
+
+// HashKeyfile returns the SHA3-256 digest of the keyfile, which can be any non empty file
+// i.e. on a USB stick, to be used as a protection factor of the seed
+func HashKeyfile(filename string) (digest *[32]byte, err error) {
+	content := new([]byte)
+	*content, err = ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(*content) == 0 {
+		return nil, errors.New("The keyfile '" + filename + "' is empty")
+	}
+	return HashAndDestroy(content), nil
+}
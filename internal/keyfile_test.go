@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+func TestHashKeyfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "derivatex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "keyfile")
+	err = ioutil.WriteFile(filename, []byte("keyfile content"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := HashKeyfile(filename)
+	if err != nil {
+		t.Fatalf("HashKeyfile() - %s", err)
+	}
+	if *digest != sha3.Sum256([]byte("keyfile content")) {
+		t.Errorf("HashKeyfile() == %v is not the SHA3-256 digest of the file", *digest)
+	}
+	emptyFilename := filepath.Join(dir, "empty")
+	err = ioutil.WriteFile(emptyFilename, []byte{}, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = HashKeyfile(emptyFilename)
+	if err == nil {
+		t.Errorf("HashKeyfile() of an empty file did not return an error")
+	}
+	_, err = HashKeyfile(filepath.Join(dir, "missing"))
+	if err == nil {
+		t.Errorf("HashKeyfile() of a missing file did not return an error")
+	}
+}
@@ -0,0 +1,44 @@
+package internal
+
+// Keys derived from the seed, which are recorded in the database so that they can be derived again identically
+
+// Types of the keys derived from the seed
+const (
+	KeyTypeSSH = "ssh"
+	KeyTypePGP = "pgp"
+)
+
+// KeyIdentificationType contains the settings of a key derived from the seed
+type KeyIdentificationType struct {
+	Type              string
+	Label             string
+	DerivationVersion uint16
+	CreationTime      int64
+	UserID            string // comment of a SSH key or user ID of an OpenPGP key
+}
+
+func createKeysTableIfNeeded() (err error) {
+	_, err = database.Exec("CREATE TABLE IF NOT EXISTS keys (type TEXT, label TEXT, derivation_version INTEGER, creation_time INTEGER, user_id TEXT NOT NULL DEFAULT '', PRIMARY KEY(type, label))")
+	return err
+}
+
+// FindKeyIdentification returns the settings of the key of the type and label, and whether it was found
+func FindKeyIdentification(keyType, label string) (key KeyIdentificationType, found bool, err error) {
+	rows, err := database.Query("SELECT type, label, derivation_version, creation_time, user_id FROM keys WHERE type = ? AND label = ?", keyType, label)
+	if err != nil {
+		return key, false, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = rows.Scan(&key.Type, &key.Label, &key.DerivationVersion, &key.CreationTime, &key.UserID)
+		return key, err == nil, err
+	}
+	return key, false, nil
+}
+
+// InsertKeyIdentification saves the settings of the key, replacing the settings of the same type and label
+func InsertKeyIdentification(key KeyIdentificationType) (err error) {
+	_, err = database.Exec("INSERT OR REPLACE INTO keys (type, label, derivation_version, creation_time, user_id) VALUES (?, ?, ?, ?, ?)",
+		key.Type, key.Label, key.DerivationVersion, key.CreationTime, key.UserID)
+	return err
+}
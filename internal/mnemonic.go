@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Similarly to Checksumize, the checksum appended to the data before encoding it
+// as words is the start of its SHA3-256 digest, taking at least mnemonicMinChecksumBits
+// bits and as many more as needed to fill the last word of 11 bits.
+// The data length must be a multiple of 4 bytes for the number of words to
+// correspond to a single data length.
+const (
+	mnemonicBitsPerWord      = 11
+	mnemonicDataLengthStep   = 4
+	mnemonicMinChecksumBits  = 16
+	mnemonicMaxChecksumBytes = 4
+)
+
+var bip39WordIndexes = makeWordIndexes(bip39EnglishWords)
+
+func makeWordIndexes(words []string) (indexes map[string]uint16) {
+	indexes = make(map[string]uint16, len(words))
+	for i, word := range words {
+		indexes[word] = uint16(i)
+	}
+	return indexes
+}
+
+func mnemonicChecksumBits(dataLength int) int {
+	bits := dataLength*8 + mnemonicMinChecksumBits
+	return mnemonicMinChecksumBits + (mnemonicBitsPerWord-bits%mnemonicBitsPerWord)%mnemonicBitsPerWord
+}
+
+// getBit returns the bit at position i of data, starting from the most significant bit
+func getBit(data []byte, i int) uint16 {
+	return uint16(data[i/8]>>(7-uint(i%8))) & 1
+}
+
+// MakeMnemonic encodes the data with its checksum as BIP39 English words
+func MakeMnemonic(data *[]byte) (words []string, err error) {
+	if len(*data) == 0 || len(*data)%mnemonicDataLengthStep != 0 {
+		return nil, errors.New("Data length must be a non zero multiple of " + strconv.Itoa(mnemonicDataLengthStep) + " bytes")
+	}
+	checksumBits := mnemonicChecksumBits(len(*data))
+	digest := sha3.Sum256(*data)
+	buffer := new([]byte)
+	*buffer = append(*buffer, *data...)
+	*buffer = append(*buffer, digest[:mnemonicMaxChecksumBytes]...)
+	totalBits := len(*data)*8 + checksumBits
+	for i := 0; i < totalBits; i += mnemonicBitsPerWord {
+		var index uint16
+		for j := 0; j < mnemonicBitsPerWord; j++ {
+			index = index<<1 | getBit(*buffer, i+j)
+		}
+		words = append(words, bip39EnglishWords[index])
+	}
+	ClearByteSlice(buffer)
+	return words, nil
+}
+
+// ParseMnemonic decodes the words into data and verifies its checksum
+func ParseMnemonic(words []string) (data *[]byte, err error) {
+	totalBits := len(words) * mnemonicBitsPerWord
+	dataLength := (totalBits - mnemonicMinChecksumBits) / 8 / mnemonicDataLengthStep * mnemonicDataLengthStep
+	if dataLength <= 0 || dataLength*8+mnemonicChecksumBits(dataLength) != totalBits {
+		return nil, errors.New(strconv.Itoa(len(words)) + " words is not a valid mnemonic length")
+	}
+	buffer := new([]byte)
+	*buffer = make([]byte, (totalBits+7)/8)
+	defer ClearByteSlice(buffer)
+	for i, word := range words {
+		index, ok := bip39WordIndexes[strings.ToLower(word)]
+		if !ok {
+			return nil, errors.New("Word " + strconv.Itoa(i+1) + " '" + word + "' is not in the word list")
+		}
+		for j := 0; j < mnemonicBitsPerWord; j++ {
+			bit := byte(index>>uint(mnemonicBitsPerWord-1-j)) & 1
+			position := i*mnemonicBitsPerWord + j
+			(*buffer)[position/8] |= bit << (7 - uint(position%8))
+		}
+	}
+	data = new([]byte)
+	*data = append(*data, (*buffer)[:dataLength]...)
+	digest := sha3.Sum256(*data)
+	checksumBits := mnemonicChecksumBits(dataLength)
+	for i := 0; i < checksumBits; i++ {
+		if getBit(*buffer, dataLength*8+i) != getBit(digest[:], i) {
+			ClearByteSlice(data)
+			return nil, errors.New("Checksum verification failed")
+		}
+	}
+	return data, nil
+}
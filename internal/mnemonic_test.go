@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_bip39EnglishWords(t *testing.T) {
+	// CRC32 of https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+	checksum := crc32.ChecksumIEEE([]byte(bip39English + "\n"))
+	if checksum != 0xc1dbd296 || len(bip39EnglishWords) != 2048 {
+		t.Errorf("BIP39 English word list is corrupted (crc32 %x, %d words)", checksum, len(bip39EnglishWords))
+	}
+}
+
+func TestMakeMnemonic(t *testing.T) {
+	cases := []struct {
+		data  []byte
+		words string
+		err   error
+	}{
+		{
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			"abandon amount liar amount expire adjust cage candy arch gather drum bunker nerve bird",
+			nil,
+		},
+		{
+			make([]byte, 64),
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon tiger",
+			nil,
+		},
+		{
+			[]byte{0, 1},
+			"",
+			errors.New("Data length must be a non zero multiple of 4 bytes"),
+		},
+	}
+	for _, c := range cases {
+		out, err := MakeMnemonic(&c.data)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("MakeMnemonic(%v) - %s", c.data, m)
+		}
+		if strings.Join(out, " ") != c.words {
+			t.Errorf("MakeMnemonic(%v) == %s want %s", c.data, strings.Join(out, " "), c.words)
+		}
+	}
+}
+
+func TestParseMnemonic(t *testing.T) {
+	cases := []struct {
+		words string
+		data  []byte
+		err   error
+	}{
+		{
+			"abandon amount liar amount expire adjust cage candy arch gather drum bunker nerve bird",
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			nil,
+		},
+		{
+			"ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON ABANDON TIGER",
+			make([]byte, 64),
+			nil,
+		},
+		{
+			"abandon abandon abandon",
+			nil,
+			errors.New("3 words is not a valid mnemonic length"),
+		},
+		{
+			"abandon bitcoin liar amount expire adjust cage candy arch gather drum bunker nerve bird",
+			nil,
+			errors.New("Word 2 'bitcoin' is not in the word list"),
+		},
+		{
+			"abandon amount liar amount expire adjust cage candy arch gather drum bunker nerve birth",
+			nil,
+			errors.New("Checksum verification failed"),
+		},
+	}
+	for _, c := range cases {
+		out, err := ParseMnemonic(strings.Fields(c.words))
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("ParseMnemonic(%s) - %s", c.words, m)
+		}
+		if err == nil && !reflect.DeepEqual(*out, c.data) {
+			t.Errorf("ParseMnemonic(%s) == %v want %v", c.words, *out, c.data)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
+)
+
+// One time password types of an identification, none by default
+const (
+	OTPTypeNone = ""
+	OTPTypeTOTP = "totp" // RFC 6238, time based
+	OTPTypeHOTP = "hotp" // RFC 4226, counter based
+)
+
+// Domain separation strings of the one time password secrets, derived from the key of the identification
+const (
+	otpDerivationSecret     = "otp secret"
+	otpDerivationEncryption = "otp secret encryption"
+)
+
+// otpKeyDerivationVersion is the password derivation version of the key of the identification the secrets are derived from
+const otpKeyDerivationVersion = 4
+
+// otpSecretSize is the size of the derived secrets, 160 bits as recommended by RFC 4226
+const otpSecretSize = 20
+
+var otpAlgorithms = map[string]func() hash.Hash{
+	"SHA1":   sha1.New,
+	"SHA256": sha256.New,
+	"SHA512": sha512.New,
+}
+
+// OTPOptionsType contains the options of the one time password of an identification, stored as JSON in the identification
+type OTPOptionsType struct {
+	Algorithm string `json:"algorithm"`         // SHA1, SHA256 or SHA512
+	Digits    int    `json:"digits"`            // 6 to 8
+	Period    int    `json:"period,omitempty"`  // seconds, for TOTP
+	Counter   uint64 `json:"counter,omitempty"` // counter of the next code, for HOTP
+	Round     uint16 `json:"round,omitempty"`   // round of the derived secret, to renew it
+}
+
+// DefaultOTPOptions returns the options supported by all authenticator applications
+func DefaultOTPOptions(otpType string) OTPOptionsType {
+	options := OTPOptionsType{Algorithm: "SHA1", Digits: 6}
+	if otpType == OTPTypeTOTP {
+		options.Period = 30
+	}
+	return options
+}
+
+func (options OTPOptionsType) String() string {
+	b, _ := json.Marshal(options)
+	return string(b)
+}
+
+// ParseOTPOptions reads options serialized with String
+func ParseOTPOptions(s string) (options OTPOptionsType, err error) {
+	err = json.Unmarshal([]byte(s), &options)
+	if err != nil {
+		return options, errors.New("OTP options '" + s + "' are invalid: " + err.Error())
+	}
+	return options, nil
+}
+
+// Validate verifies the options are supported for the one time password type
+func (options OTPOptionsType) Validate(otpType string) error {
+	if otpType != OTPTypeTOTP && otpType != OTPTypeHOTP {
+		return errors.New("OTP type '" + otpType + "' is not supported, use totp or hotp")
+	}
+	if _, ok := otpAlgorithms[options.Algorithm]; !ok {
+		return errors.New("OTP algorithm '" + options.Algorithm + "' is not supported, use SHA1, SHA256 or SHA512")
+	}
+	if options.Digits < 6 || options.Digits > 8 {
+		return errors.New("OTP codes must have between 6 and 8 digits")
+	}
+	if otpType == OTPTypeTOTP && options.Period < 1 {
+		return errors.New("The period of time based codes must be at least 1 second")
+	}
+	return nil
+}
+
+// DeriveOTPSecret derives the secret of the one time passwords of the identification from its key and the round of the options
+func DeriveOTPSecret(identificationKey *[32]byte, round uint16) (secret []byte) {
+	info := []byte(otpDerivationSecret)
+	info = append(info, byte(round>>8), byte(round))
+	secret = make([]byte, otpSecretSize)
+	io.ReadFull(hkdf.Expand(sha3.New256, (*identificationKey)[:], info), secret)
+	return secret
+}
+
+// otpEncryptionKey returns the key encrypting the imported secret of the identification, derived from its key
+func otpEncryptionKey(identificationKey *[32]byte) (key *[32]byte) {
+	key = new([32]byte)
+	io.ReadFull(hkdf.Expand(sha3.New256, (*identificationKey)[:], []byte(otpDerivationEncryption)), (*key)[:])
+	return key
+}
+
+// EncryptOTPSecret encrypts an imported secret with a key derived from the key of the identification,
+// authenticating the website and user, and returns it encoded in base64 for the database
+func EncryptOTPSecret(identificationKey *[32]byte, website, user string, secret []byte) (encrypted string, err error) {
+	key := otpEncryptionKey(identificationKey)
+	defer ClearByteArray32(key)
+	additionalData := append(lengthPrefixed(website), lengthPrefixed(user)...)
+	ciphertext, err := EncryptAESGCM(&secret, key, additionalData, io.ReadFull)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(*ciphertext), nil
+}
+
+// DecryptOTPSecret decrypts a secret encrypted with EncryptOTPSecret
+func DecryptOTPSecret(identificationKey *[32]byte, website, user string, encrypted string) (secret []byte, err error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, errors.New("The encrypted OTP secret is malformed (" + err.Error() + ")")
+	}
+	key := otpEncryptionKey(identificationKey)
+	defer ClearByteArray32(key)
+	additionalData := append(lengthPrefixed(website), lengthPrefixed(user)...)
+	plaintext, err := DecryptAESGCM(&ciphertext, key, additionalData)
+	if err != nil {
+		return nil, errors.New("The OTP secret can't be decrypted with this seed (" + err.Error() + ")")
+	}
+	return *plaintext, nil
+}
+
+// OTPSecretFromSeed returns the secret of the one time passwords of the identification, decrypting
+// it if it was imported or deriving it from the seed otherwise
+func (identification *IdentificationType) OTPSecretFromSeed(seed *[]byte) (secret []byte, err error) {
+	if identification.OTPType == OTPTypeNone {
+		return nil, errors.New("No one time password is set for " + identification.Website + " and " + identification.User)
+	}
+	options, err := ParseOTPOptions(identification.OTPOptions)
+	if err != nil {
+		return nil, err
+	}
+	identificationKey := MakePasswordDigest(seed, identification.Website, identification.User, otpKeyDerivationVersion)
+	defer ClearByteArray32(identificationKey)
+	if identification.OTPSecret != "" {
+		return DecryptOTPSecret(identificationKey, identification.Website, identification.User, identification.OTPSecret)
+	}
+	return DeriveOTPSecret(identificationKey, options.Round), nil
+}
+
+// SetImportedOTPSecret encrypts the imported secret with a key derived from the seed and sets it as the secret of the identification
+func (identification *IdentificationType) SetImportedOTPSecret(seed *[]byte, secret []byte) (err error) {
+	identificationKey := MakePasswordDigest(seed, identification.Website, identification.User, otpKeyDerivationVersion)
+	defer ClearByteArray32(identificationKey)
+	identification.OTPSecret, err = EncryptOTPSecret(identificationKey, identification.Website, identification.User, secret)
+	return err
+}
+
+// OTPTypeString returns the one time password type of the identification for display
+func (identification *IdentificationType) OTPTypeString() string {
+	if identification.OTPType == OTPTypeNone {
+		return ""
+	}
+	if identification.OTPSecret != "" {
+		return identification.OTPType + " (imported)"
+	}
+	return identification.OTPType + " (derived)"
+}
+
+// HOTPCode returns the code of the counter as specified by RFC 4226, with the HMAC algorithm of the options
+func HOTPCode(secret []byte, counter uint64, options OTPOptionsType) (code string, err error) {
+	newHash, ok := otpAlgorithms[options.Algorithm]
+	if !ok {
+		return "", errors.New("OTP algorithm '" + options.Algorithm + "' is not supported, use SHA1, SHA256 or SHA512")
+	}
+	if options.Digits < 1 || options.Digits > 9 {
+		return "", errors.New("OTP codes must have between 1 and 9 digits")
+	}
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+	mac := hmac.New(newHash, secret)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f // dynamic truncation
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for i := 0; i < options.Digits; i++ {
+		modulo *= 10
+	}
+	code = strconv.FormatUint(uint64(value%modulo), 10)
+	return strings.Repeat("0", options.Digits-len(code)) + code, nil
+}
+
+// TOTPCode returns the code at the time as specified by RFC 6238 and the time remaining before the next code
+func TOTPCode(secret []byte, t time.Time, options OTPOptionsType) (code string, remaining time.Duration, err error) {
+	if options.Period < 1 {
+		return "", 0, errors.New("The period of time based codes must be at least 1 second")
+	}
+	seconds := t.Unix()
+	period := int64(options.Period)
+	code, err = HOTPCode(secret, uint64(seconds/period), options)
+	remaining = time.Duration(period-seconds%period) * time.Second
+	return code, remaining, err
+}
+
+var otpBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// OTPAuthURI returns the otpauth URI of the secret, as read by authenticator applications, the website being the issuer
+func OTPAuthURI(otpType, website, user string, secret []byte, options OTPOptionsType) string {
+	query := url.Values{}
+	query.Set("secret", otpBase32.EncodeToString(secret))
+	query.Set("issuer", website)
+	query.Set("algorithm", options.Algorithm)
+	query.Set("digits", strconv.Itoa(options.Digits))
+	if otpType == OTPTypeTOTP {
+		query.Set("period", strconv.Itoa(options.Period))
+	} else {
+		query.Set("counter", strconv.FormatUint(options.Counter, 10))
+	}
+	label := url.PathEscape(website + ":" + user)
+	return "otpauth://" + otpType + "/" + label + "?" + query.Encode()
+}
+
+// ParseOTPSecret reads an otpauth URI or a base32 secret, the latter being a time based secret with the default options
+func ParseOTPSecret(s string) (otpType string, secret []byte, options OTPOptionsType, err error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		secret, err = decodeOTPBase32(s)
+		return OTPTypeTOTP, secret, DefaultOTPOptions(OTPTypeTOTP), err
+	}
+	uri, err := url.Parse(s)
+	if err != nil {
+		return "", nil, options, errors.New("The otpauth URI is invalid (" + err.Error() + ")")
+	}
+	otpType = strings.ToLower(uri.Host)
+	options = DefaultOTPOptions(otpType)
+	query := uri.Query()
+	secret, err = decodeOTPBase32(query.Get("secret"))
+	if err != nil {
+		return "", nil, options, err
+	}
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		options.Algorithm = strings.ToUpper(algorithm)
+	}
+	for name, value := range map[string]*int{"digits": &options.Digits, "period": &options.Period} {
+		if query.Get(name) == "" {
+			continue
+		}
+		*value, err = strconv.Atoi(query.Get(name))
+		if err != nil {
+			return "", nil, options, errors.New("The " + name + " of the otpauth URI is not a number")
+		}
+	}
+	if otpType == OTPTypeHOTP {
+		options.Counter, err = strconv.ParseUint(query.Get("counter"), 10, 64)
+		if err != nil {
+			return "", nil, options, errors.New("The counter of the otpauth URI is not a number")
+		}
+	}
+	return otpType, secret, options, options.Validate(otpType)
+}
+
+// decodeOTPBase32 decodes a base32 secret, ignoring spaces, dashes, padding and the case
+func decodeOTPBase32(s string) (secret []byte, err error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(s))
+	secret, err = otpBase32.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("The OTP secret is not valid base32 (" + err.Error() + ")")
+	}
+	if len(secret) < 10 {
+		return nil, errors.New("The OTP secret must be at least 80 bits long")
+	}
+	return secret, nil
+}
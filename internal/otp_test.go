@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHOTPCode(t *testing.T) {
+	// RFC 4226 appendix D
+	secret := []byte("12345678901234567890")
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range expected {
+		out, err := HOTPCode(secret, uint64(counter), DefaultOTPOptions(OTPTypeHOTP))
+		if err != nil || out != code {
+			t.Errorf("HOTPCode(%d) == %s, %v want %s", counter, out, err, code)
+		}
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B
+	secrets := map[string][]byte{
+		"SHA1":   []byte("12345678901234567890"),
+		"SHA256": []byte("12345678901234567890123456789012"),
+		"SHA512": []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	cases := []struct {
+		seconds   int64
+		algorithm string
+		code      string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1111111109, "SHA256", "68084774"},
+		{1111111109, "SHA512", "25091201"},
+		{1234567890, "SHA1", "89005924"},
+		{2000000000, "SHA256", "90698825"},
+		{20000000000, "SHA512", "47863826"},
+	}
+	for _, c := range cases {
+		options := OTPOptionsType{Algorithm: c.algorithm, Digits: 8, Period: 30}
+		code, remaining, err := TOTPCode(secrets[c.algorithm], time.Unix(c.seconds, 0), options)
+		if err != nil || code != c.code {
+			t.Errorf("TOTPCode(%d, %s) == %s, %v want %s", c.seconds, c.algorithm, code, err, c.code)
+		}
+		if expected := time.Duration(30-c.seconds%30) * time.Second; remaining != expected {
+			t.Errorf("TOTPCode(%d, %s) remaining == %s want %s", c.seconds, c.algorithm, remaining, expected)
+		}
+	}
+}
+
+func TestOTPAuthURI(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	options := DefaultOTPOptions(OTPTypeTOTP)
+	uri := OTPAuthURI(OTPTypeTOTP, "my site", "a@b.c", secret, options)
+	const expected = "otpauth://totp/my%20site:a@b.c?algorithm=SHA1&digits=6&issuer=my+site&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	if uri != expected {
+		t.Errorf("OTPAuthURI() == %s want %s", uri, expected)
+	}
+	otpType, parsedSecret, parsedOptions, err := ParseOTPSecret(uri)
+	if err != nil || otpType != OTPTypeTOTP || !bytes.Equal(parsedSecret, secret) || parsedOptions != options {
+		t.Errorf("ParseOTPSecret(%s) == %s, %v, %v, %v", uri, otpType, parsedSecret, parsedOptions, err)
+	}
+	options = OTPOptionsType{Algorithm: "SHA256", Digits: 8, Counter: 5}
+	uri = OTPAuthURI(OTPTypeHOTP, "site", "user", secret, options)
+	otpType, _, parsedOptions, err = ParseOTPSecret(uri)
+	if err != nil || otpType != OTPTypeHOTP || parsedOptions != options {
+		t.Errorf("ParseOTPSecret(%s) == %s, %v, %v", uri, otpType, parsedOptions, err)
+	}
+	otpType, parsedSecret, parsedOptions, err = ParseOTPSecret("gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+	if err != nil || otpType != OTPTypeTOTP || !bytes.Equal(parsedSecret, secret) || parsedOptions != DefaultOTPOptions(OTPTypeTOTP) {
+		t.Errorf("ParseOTPSecret() of a base32 secret == %s, %v, %v, %v", otpType, parsedSecret, parsedOptions, err)
+	}
+	for _, s := range []string{"GEZDGNBV", "not base32!", "otpauth://totp/x?secret=GEZDGNBVGY3TQOJQ&algorithm=MD5", "otpauth://hotp/x?secret=GEZDGNBVGY3TQOJQ", "otpauth://motp/x?secret=GEZDGNBVGY3TQOJQ"} {
+		_, _, _, err := ParseOTPSecret(s)
+		if err == nil {
+			t.Errorf("ParseOTPSecret(%s) should fail", s)
+		}
+	}
+}
+
+func TestOTPSecretFromSeed(t *testing.T) {
+	seed := []byte{1, 2, 3, 4}
+	derived := IdentificationType{Website: "website", User: "user", OTPType: OTPTypeTOTP, OTPOptions: DefaultOTPOptions(OTPTypeTOTP).String()}
+	secret, err := derived.OTPSecretFromSeed(&seed)
+	if err != nil {
+		t.Fatalf("OTPSecretFromSeed() - %s", err)
+	}
+	again, _ := derived.OTPSecretFromSeed(&seed)
+	if len(secret) != 20 || !bytes.Equal(secret, again) {
+		t.Errorf("OTPSecretFromSeed() == %x and %x want the same 20 bytes", secret, again)
+	}
+	renewed := derived
+	renewed.OTPOptions = OTPOptionsType{Algorithm: "SHA1", Digits: 6, Period: 30, Round: 2}.String()
+	if other, _ := renewed.OTPSecretFromSeed(&seed); bytes.Equal(secret, other) {
+		t.Errorf("OTPSecretFromSeed() is the same for the rounds 1 and 2")
+	}
+
+	imported := IdentificationType{Website: "website", User: "user", OTPType: OTPTypeTOTP, OTPOptions: derived.OTPOptions}
+	err = imported.SetImportedOTPSecret(&seed, []byte("12345678901234567890"))
+	if err != nil {
+		t.Fatalf("SetImportedOTPSecret() - %s", err)
+	}
+	if strings.Contains(imported.OTPSecret, "12345678901234567890") {
+		t.Errorf("SetImportedOTPSecret() stored the secret in clear")
+	}
+	secret, err = imported.OTPSecretFromSeed(&seed)
+	if err != nil || string(secret) != "12345678901234567890" {
+		t.Errorf("OTPSecretFromSeed() of an imported secret == %q, %v", secret, err)
+	}
+	otherSeed := []byte{4, 3, 2, 1}
+	if _, err = imported.OTPSecretFromSeed(&otherSeed); err == nil {
+		t.Errorf("OTPSecretFromSeed() decrypted the imported secret with another seed")
+	}
+	moved := imported
+	moved.User = "other"
+	if _, err = moved.OTPSecretFromSeed(&seed); err == nil {
+		t.Errorf("OTPSecretFromSeed() decrypted the imported secret of another user")
+	}
+	if _, err = (&IdentificationType{}).OTPSecretFromSeed(&seed); err == nil {
+		t.Errorf("OTPSecretFromSeed() without one time password should fail")
+	}
+}
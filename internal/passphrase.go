@@ -2,7 +2,6 @@ package internal
 
 import (
 	"github.com/castillobgr/sententia"
-	"github.com/techsek/derivatex/constants"
 	"golang.org/x/crypto/argon2"
 )
 
@@ -15,9 +14,9 @@ func MakePassphrase() (passphrase string, err error) {
 	return passphrase, nil
 }
 
-func MakeKey(passphrase *[]byte) (key *[32]byte) {
+func MakeKey(passphrase *[]byte, params ArgonParamsType, salt []byte) (key *[32]byte) {
 	key = new([32]byte)
-	keySlice := argon2.IDKey(*passphrase, []byte{}, constants.PassphraseArgonTimeCost, constants.PassphraseArgonMemoryMB*1024, constants.PassphraseArgonParallelism, 32)
+	keySlice := argon2.IDKey(*passphrase, salt, params.TimeCost, params.MemoryKB, params.Parallelism, 32)
 	copy((*key)[:], keySlice)
 	ClearByteSlice(&keySlice)
 	return key
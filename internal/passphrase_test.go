@@ -20,7 +20,7 @@ func TestMakeKey(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		out := MakeKey(&c.passphrase)
+		out := MakeKey(&c.passphrase, legacyPassphraseArgonParams, []byte{})
 		if !reflect.DeepEqual(*out, c.key) {
 			t.Errorf("MakeKey(%v) == %v want %v", c.passphrase, *out, c.key)
 		}
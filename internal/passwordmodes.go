@@ -0,0 +1,365 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Password modes of an identification, the password length being the number of words in the words mode
+const (
+	PasswordModeCharacters    = ""
+	PasswordModeWords         = "words"
+	PasswordModePin           = "pin"
+	PasswordModeReadable      = "readable"
+	PasswordModePronounceable = "pronounceable"
+	PasswordModeAlphabet      = "alphabet"
+)
+
+const passwordDerivationV4Words = "words"
+const passwordDerivationV4Pin = "pin"
+const passwordDerivationV4Pronounceable = "pronounceable"
+const passwordDerivationV4Alphabet = "alphabet"
+
+// ReadableExcludedCharacters are the characters excluded in the readable mode as they can be confused with others
+const ReadableExcludedCharacters = "0Oo1lIi|!`'\",.;:"
+
+// Letters of the syllables of the pronounceable mode, consonant vowel consonant
+const (
+	pronounceableConsonants = "bcdfghjklmnprstvz"
+	pronounceableVowels     = "aeiou"
+)
+
+// WordsOptionsType contains the options of the words mode, stored as JSON in the identification
+type WordsOptionsType struct {
+	Separator  string `json:"separator"`
+	Capitalize bool   `json:"capitalize"`
+}
+
+func (options WordsOptionsType) String() string {
+	b, _ := json.Marshal(options)
+	return string(b)
+}
+
+// ParseWordsOptions reads options serialized with String
+func ParseWordsOptions(s string) (options WordsOptionsType, err error) {
+	err = json.Unmarshal([]byte(s), &options)
+	if err != nil {
+		return options, errors.New("Words options '" + s + "' are invalid: " + err.Error())
+	}
+	return options, nil
+}
+
+// SatisfyWords derives a passphrase of words drawn uniformly from the BIP39 English word list,
+// giving 11 bits of entropy per word, from a HKDF stream of the identification key and round
+func SatisfyWords(passwordDigest *[32]byte, words int, round uint16, options WordsOptionsType) string {
+	info := []byte(passwordDerivationV4Words)
+	info = append(info, byte(round>>8), byte(round))
+	stream := newHKDFStream((*passwordDigest)[:], info)
+	chosen := make([]string, words)
+	for i := range chosen {
+		chosen[i] = bip39EnglishWords[stream.uniformInt(len(bip39EnglishWords))]
+		if options.Capitalize {
+			chosen[i] = strings.ToUpper(chosen[i][:1]) + chosen[i][1:]
+		}
+	}
+	return strings.Join(chosen, options.Separator)
+}
+
+// PinOptionsType contains the options of the pin mode, stored as JSON in the identification
+type PinOptionsType struct {
+	RejectWeak bool `json:"reject_weak"`
+}
+
+func (options PinOptionsType) String() string {
+	b, _ := json.Marshal(options)
+	return string(b)
+}
+
+// ParsePinOptions reads options serialized with String
+func ParsePinOptions(s string) (options PinOptionsType, err error) {
+	err = json.Unmarshal([]byte(s), &options)
+	if err != nil {
+		return options, errors.New("Pin options '" + s + "' are invalid: " + err.Error())
+	}
+	return options, nil
+}
+
+// SatisfyPin derives a code of uniformly distributed digits from a HKDF stream of the identification key and round,
+// the whole code being drawn again while it is weak if weak codes are rejected
+func SatisfyPin(passwordDigest *[32]byte, length int, round uint16, options PinOptionsType) string {
+	info := []byte(passwordDerivationV4Pin)
+	info = append(info, byte(round>>8), byte(round))
+	stream := newHKDFStream((*passwordDigest)[:], info)
+	pin := make([]byte, length)
+	for {
+		for i := range pin {
+			pin[i] = byte('0' + stream.uniformInt(10))
+		}
+		if !options.RejectWeak || !IsWeakPin(string(pin)) {
+			return string(pin)
+		}
+	}
+}
+
+// IsWeakPin returns true if the code is a repeated group of digits (1111, 1212), an ascending or
+// descending sequence (1234, 9876) or a date (0512, 1985, 120599, 19851205)
+func IsWeakPin(pin string) bool {
+	for period := 1; period < len(pin); period++ {
+		if len(pin)%period == 0 && strings.Repeat(pin[:period], len(pin)/period) == pin {
+			return true
+		}
+	}
+	ascending, descending := true, true
+	for i := 1; i < len(pin); i++ {
+		ascending = ascending && pin[i] == pin[i-1]+1
+		descending = descending && pin[i] == pin[i-1]-1
+	}
+	if ascending || descending {
+		return true
+	}
+	switch len(pin) {
+	case 4:
+		return isDate(pin[:2], pin[2:], "") || isDate(pin[2:], pin[:2], "") || isYear(pin)
+	case 6:
+		return isDate(pin[:2], pin[2:4], pin[4:]) || isDate(pin[2:4], pin[:2], pin[4:]) || isDate(pin[4:], pin[2:4], pin[:2])
+	case 8:
+		return ((isDate(pin[:2], pin[2:4], "") || isDate(pin[2:4], pin[:2], "")) && isYear(pin[4:])) ||
+			(isDate(pin[6:], pin[4:6], "") && isYear(pin[:4]))
+	}
+	return false
+}
+
+// isDate returns true if the two digits day and month are a valid date, of the two digits year if given
+func isDate(day, month, year string) bool {
+	d, _ := strconv.Atoi(day)
+	m, _ := strconv.Atoi(month)
+	if m < 1 || m > 12 || d < 1 {
+		return false
+	}
+	daysInMonth := [13]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if m == 2 && year != "" {
+		if y, _ := strconv.Atoi(year); y%4 != 0 {
+			return d <= 28
+		}
+	}
+	return d <= daysInMonth[m]
+}
+
+// isYear returns true if the four digits are a year from 1900 to 2099
+func isYear(year string) bool {
+	y, _ := strconv.Atoi(year)
+	return y >= 1900 && y <= 2099
+}
+
+// SatisfyPronounceable derives a password of syllables made of a consonant, a vowel and a consonant, the last syllable
+// being cut to the password length, from a HKDF stream of the identification key and round.
+// The first consonant of each syllable is uppercase one time out of two.
+func SatisfyPronounceable(passwordDigest *[32]byte, passwordLength int, round uint16) string {
+	info := []byte(passwordDerivationV4Pronounceable)
+	info = append(info, byte(round>>8), byte(round))
+	stream := newHKDFStream((*passwordDigest)[:], info)
+	password := make([]byte, passwordLength)
+	for i := range password {
+		switch i % 3 {
+		case 0:
+			password[i] = pronounceableConsonants[stream.uniformInt(len(pronounceableConsonants))]
+			if stream.uniformInt(2) == 1 {
+				password[i] -= 'a' - 'A'
+			}
+		case 1:
+			password[i] = pronounceableVowels[stream.uniformInt(len(pronounceableVowels))]
+		case 2:
+			password[i] = pronounceableConsonants[stream.uniformInt(len(pronounceableConsonants))]
+		}
+	}
+	return string(password)
+}
+
+// PasswordAlphabets are the named alphabets of the alphabet mode
+var PasswordAlphabets = map[string]string{
+	"alphanumeric": "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+	"base32":       "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567",
+	"base58":       "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz",
+	"base64url":    "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_",
+	"hex":          "0123456789abcdef",
+}
+
+// AlphabetOptionsType contains the options of the alphabet mode, stored as JSON in the identification
+type AlphabetOptionsType struct {
+	Alphabet string `json:"alphabet"` // characters, never the name of the alphabet so that it can't change
+}
+
+func (options AlphabetOptionsType) String() string {
+	b, _ := json.Marshal(options)
+	return string(b)
+}
+
+// ParseAlphabetOptions reads options serialized with String
+func ParseAlphabetOptions(s string) (options AlphabetOptionsType, err error) {
+	err = json.Unmarshal([]byte(s), &options)
+	if err != nil {
+		return options, errors.New("Alphabet options '" + s + "' are invalid: " + err.Error())
+	}
+	return options, ValidateAlphabet(options.Alphabet)
+}
+
+// ResolveAlphabet returns the characters of a named alphabet of PasswordAlphabets, or else the characters given
+func ResolveAlphabet(nameOrCharacters string) (alphabet string, err error) {
+	alphabet, ok := PasswordAlphabets[nameOrCharacters]
+	if !ok {
+		alphabet = nameOrCharacters
+	}
+	return alphabet, ValidateAlphabet(alphabet)
+}
+
+// ValidateAlphabet verifies the alphabet has at least 2 different characters, each only once and none being a space or a control character
+func ValidateAlphabet(alphabet string) error {
+	if !utf8.ValidString(alphabet) {
+		return errors.New("The alphabet is not valid UTF-8")
+	}
+	seen := make(map[rune]bool)
+	for _, r := range alphabet {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return errors.New("The alphabet must not contain spaces nor control characters")
+		}
+		if seen[r] {
+			return errors.New("The alphabet contains '" + string(r) + "' more than once")
+		}
+		seen[r] = true
+	}
+	if len(seen) < 2 {
+		return errors.New("The alphabet must contain at least 2 characters")
+	}
+	return nil
+}
+
+// SatisfyAlphabet derives a password of characters drawn uniformly from the alphabet, from a HKDF stream of the identification key and round
+func SatisfyAlphabet(passwordDigest *[32]byte, passwordLength int, round uint16, alphabet string) string {
+	info := []byte(passwordDerivationV4Alphabet)
+	info = append(info, byte(round>>8), byte(round))
+	stream := newHKDFStream((*passwordDigest)[:], info)
+	characters := []rune(alphabet)
+	password := make([]rune, passwordLength)
+	for i := range password {
+		password[i] = characters[stream.uniformInt(len(characters))]
+	}
+	return string(password)
+}
+
+// satisfyCharacters derives a password of characters satisfying the constraints, and the password rules of the identification if any
+func satisfyCharacters(passwordDigest *[32]byte, identification *IdentificationType, constraints CharacterConstraintsType) (password string, err error) {
+	if identification.PasswordRules == "" {
+		return SatisfyPassword(passwordDigest, identification.PasswordLength, identification.Round, constraints, identification.PasswordDerivationVersion)
+	}
+	rules, err := ParsePasswordRules(identification.PasswordRules)
+	if err != nil {
+		return "", err
+	}
+	if constraints.Required != "" || len(constraints.Counts) > 0 {
+		return "", errors.New("Required characters and counts of characters can't be used with password rules")
+	}
+	return SatisfyPasswordRules(passwordDigest, int(identification.PasswordLength), identification.Round, rules, BuildUnallowedCharacters(constraints))
+}
+
+// readableConstraints returns the constraints of the identification also forbidding the ReadableExcludedCharacters
+func (identification *IdentificationType) readableConstraints() CharacterConstraintsType {
+	constraints := identification.Constraints
+	constraints.Forbidden = canonicalCharacterSet(constraints.Forbidden + ReadableExcludedCharacters)
+	return constraints
+}
+
+// GeneratePassword derives the password of the identification from its password digest, according to its mode
+func GeneratePassword(passwordDigest *[32]byte, identification *IdentificationType) (password string, err error) {
+	if identification.PasswordMode != PasswordModeCharacters && identification.PasswordDerivationVersion < 4 {
+		return "", errors.New("Password mode '" + identification.PasswordMode + "' requires the password derivation version 4 or above")
+	}
+	if identification.PasswordLength > 255 && identification.PasswordDerivationVersion < 4 {
+		return "", errors.New("Passwords longer than 255 characters require the password derivation version 4 or above")
+	}
+	switch identification.PasswordMode {
+	case PasswordModeCharacters:
+		return satisfyCharacters(passwordDigest, identification, identification.Constraints)
+	case PasswordModeReadable:
+		constraints := identification.readableConstraints()
+		if !constraints.IsAnythingAllowed() {
+			return "", errors.New("All the readable characters are unallowed")
+		}
+		return satisfyCharacters(passwordDigest, identification, constraints)
+	case PasswordModePronounceable:
+		return SatisfyPronounceable(passwordDigest, int(identification.PasswordLength), identification.Round), nil
+	case PasswordModeAlphabet:
+		options, err := ParseAlphabetOptions(identification.ModeOptions)
+		if err != nil {
+			return "", err
+		}
+		return SatisfyAlphabet(passwordDigest, int(identification.PasswordLength), identification.Round, options.Alphabet), nil
+	case PasswordModeWords:
+		options, err := ParseWordsOptions(identification.ModeOptions)
+		if err != nil {
+			return "", err
+		}
+		if identification.PasswordLength == 0 {
+			return "", errors.New("At least one word is required")
+		}
+		return SatisfyWords(passwordDigest, int(identification.PasswordLength), identification.Round, options), nil
+	case PasswordModePin:
+		options, err := ParsePinOptions(identification.ModeOptions)
+		if err != nil {
+			return "", err
+		}
+		if identification.PasswordLength < 4 {
+			return "", errors.New("A code must have at least 4 digits")
+		}
+		return SatisfyPin(passwordDigest, int(identification.PasswordLength), identification.Round, options), nil
+	default:
+		return "", errors.New("Password mode '" + identification.PasswordMode + "' is not supported by this program, please update it")
+	}
+}
+
+// PasswordModeString describes the password mode and its options, empty for the characters mode
+func (identification *IdentificationType) PasswordModeString() string {
+	switch identification.PasswordMode {
+	case PasswordModeCharacters:
+		return ""
+	case PasswordModeWords:
+		options, err := ParseWordsOptions(identification.ModeOptions)
+		if err != nil {
+			return identification.PasswordMode + " " + identification.ModeOptions
+		}
+		description := "words separated by '" + options.Separator + "'"
+		if options.Capitalize {
+			description += ", capitalized"
+		}
+		return description
+	case PasswordModePin:
+		options, err := ParsePinOptions(identification.ModeOptions)
+		if err != nil {
+			return identification.PasswordMode + " " + identification.ModeOptions
+		}
+		if options.RejectWeak {
+			return "digits, weak codes rejected"
+		}
+		return "digits"
+	case PasswordModeReadable:
+		return "readable characters"
+	case PasswordModePronounceable:
+		return "syllables"
+	case PasswordModeAlphabet:
+		options, err := ParseAlphabetOptions(identification.ModeOptions)
+		if err != nil {
+			return identification.PasswordMode + " " + identification.ModeOptions
+		}
+		for name, alphabet := range PasswordAlphabets {
+			if alphabet == options.Alphabet {
+				return name
+			}
+		}
+		return "alphabet '" + options.Alphabet + "'"
+	default:
+		return identification.PasswordMode + " " + identification.ModeOptions
+	}
+}
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/techsek/derivatex/constants"
+)
+
+func TestSatisfyWords(t *testing.T) {
+	seed := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+	passwordDigest := MakePasswordDigest(&seed, "google", "a@a", 4)
+	cases := []struct {
+		words    int
+		round    uint16
+		options  WordsOptionsType
+		password string
+	}{
+		{6, 1, WordsOptionsType{Separator: "-"}, "tool-clean-umbrella-record-oven-brown"},
+		{6, 2, WordsOptionsType{Separator: "-"}, "disease-slab-wear-tomorrow-front-when"},
+		{4, 1, WordsOptionsType{Separator: " ", Capitalize: true}, "Tool Clean Umbrella Record"},
+		{8, 1, WordsOptionsType{}, "toolcleanumbrellarecordovenbrownpendiamond"},
+	}
+	for _, c := range cases {
+		password := SatisfyWords(passwordDigest, c.words, c.round, c.options)
+		if password != c.password {
+			t.Errorf("SatisfyWords(%d, %d, %v) == %q want %q", c.words, c.round, c.options, password, c.password)
+		}
+	}
+}
+
+func TestIsWeakPin(t *testing.T) {
+	cases := map[string]bool{
+		"1111": true, "1212": true, "123123": true, "1234": true, "9876": true, "345678": true,
+		"0512": true, "3112": true, "1985": true, "120599": true, "990512": true, "19851205": true, "05121985": true,
+		"2902": true, "290233": false, "290232": true,
+		"3841": false, "7305": false, "1357": false, "926173": false, "48213759": false, "1235": false,
+	}
+	for pin, weak := range cases {
+		if IsWeakPin(pin) != weak {
+			t.Errorf("IsWeakPin(%s) == %t want %t", pin, !weak, weak)
+		}
+	}
+}
+
+func TestSatisfyPin(t *testing.T) {
+	seed := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+	passwordDigest := MakePasswordDigest(&seed, "google", "a@a", 4)
+	cases := []struct {
+		length  int
+		round   uint16
+		options PinOptionsType
+		pin     string
+	}{
+		{4, 1, PinOptionsType{}, "7368"},
+		{4, 1, PinOptionsType{RejectWeak: true}, "7368"},
+		{4, 3, PinOptionsType{}, "0120"},
+		{4, 3, PinOptionsType{RejectWeak: true}, "3783"},
+		{6, 1, PinOptionsType{RejectWeak: true}, "736839"},
+		{6, 2, PinOptionsType{RejectWeak: true}, "406073"},
+		{8, 1, PinOptionsType{RejectWeak: true}, "73683978"},
+	}
+	for _, c := range cases {
+		pin := SatisfyPin(passwordDigest, c.length, c.round, c.options)
+		if pin != c.pin {
+			t.Errorf("SatisfyPin(%d, %d, %v) == %q want %q", c.length, c.round, c.options, pin, c.pin)
+		}
+	}
+	for round := uint16(1); round <= 200; round++ {
+		pin := SatisfyPin(passwordDigest, 4, round, PinOptionsType{RejectWeak: true})
+		if IsWeakPin(pin) || strings.Trim(pin, "0123456789") != "" {
+			t.Errorf("SatisfyPin() returned the weak code %q for round %d", pin, round)
+		}
+	}
+}
+
+func TestSatisfyPronounceable(t *testing.T) {
+	seed := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+	passwordDigest := MakePasswordDigest(&seed, "google", "a@a", 4)
+	cases := []struct {
+		length   int
+		round    uint16
+		password string
+	}{
+		{32, 1, "nimHudnihkikmozforgajkifTosSumgi"},
+		{32, 2, "kozRehRaznublutriflijkelrobKapgo"},
+		{7, 1, "nimHudn"},
+	}
+	for _, c := range cases {
+		password := SatisfyPronounceable(passwordDigest, c.length, c.round)
+		if password != c.password {
+			t.Errorf("SatisfyPronounceable(%d, %d) == %q want %q", c.length, c.round, password, c.password)
+		}
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	seed := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+	passwordDigest := MakePasswordDigest(&seed, "google", "a@a", 4)
+	cases := []struct {
+		identification IdentificationType
+		password       string
+		err            string
+	}{
+		{IdentificationType{PasswordLength: 20, Round: 1, PasswordDerivationVersion: 4}, "cJ568'[bEw)9/Tc3|UYw", ""},
+		{IdentificationType{PasswordLength: 4, Round: 1, PasswordDerivationVersion: 4, PasswordMode: PasswordModeWords, ModeOptions: `{"separator":" ","capitalize":true}`}, "Tool Clean Umbrella Record", ""},
+		{IdentificationType{PasswordLength: 20, Round: 1, PasswordDerivationVersion: 4, PasswordMode: PasswordModeReadable}, "kP744^>bJh{5}Dk5+LJb", ""},
+		{IdentificationType{PasswordLength: 6, Round: 1, PasswordDerivationVersion: 4, PasswordMode: PasswordModePin, ModeOptions: `{"reject_weak":true}`}, "736839", ""},
+		{IdentificationType{PasswordLength: 7, Round: 1, PasswordDerivationVersion: 4, PasswordMode: PasswordModePronounceable}, "nimHudn", ""},
+		{IdentificationType{PasswordLength: 20, Round: 1, PasswordDerivationVersion: 4, PasswordMode: PasswordModeReadable, Constraints: CharacterConstraintsType{Forbidden: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ23456789" + constants.Symbols}}, "", "All the readable characters are unallowed"},
+		{IdentificationType{PasswordLength: 4, Round: 1, PasswordDerivationVersion: 3, PasswordMode: PasswordModeWords, ModeOptions: `{"separator":"-","capitalize":false}`}, "", "Password mode 'words' requires the password derivation version 4 or above"},
+		{IdentificationType{PasswordLength: 300, Round: 1, PasswordDerivationVersion: 3}, "", "Passwords longer than 255 characters require the password derivation version 4 or above"},
+		{IdentificationType{PasswordLength: 4, Round: 1, PasswordDerivationVersion: 4, PasswordMode: "emoji"}, "", "Password mode 'emoji' is not supported by this program, please update it"},
+	}
+	for _, c := range cases {
+		password, err := GeneratePassword(passwordDigest, &c.identification)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("GeneratePassword(%v) error == %v want %q", c.identification, err, c.err)
+			}
+			continue
+		}
+		if err != nil || password != c.password {
+			t.Errorf("GeneratePassword(%v) == %q, %v want %q", c.identification, password, err, c.password)
+		}
+	}
+}
+
+func TestResolveAlphabet(t *testing.T) {
+	cases := []struct {
+		nameOrCharacters string
+		alphabet         string
+		err              string
+	}{
+		{"hex", "0123456789abcdef", ""},
+		{"base32", "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567", ""},
+		{"01", "01", ""},
+		{"αβγδ", "αβγδ", ""},
+		{"a", "", "The alphabet must contain at least 2 characters"},
+		{"abca", "", "The alphabet contains 'a' more than once"},
+		{"ab c", "", "The alphabet must not contain spaces nor control characters"},
+		{"ab\xff", "", "The alphabet is not valid UTF-8"},
+	}
+	for _, c := range cases {
+		alphabet, err := ResolveAlphabet(c.nameOrCharacters)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("ResolveAlphabet(%q) error == %v want %q", c.nameOrCharacters, err, c.err)
+			}
+			continue
+		}
+		if err != nil || alphabet != c.alphabet {
+			t.Errorf("ResolveAlphabet(%q) == %q, %v want %q", c.nameOrCharacters, alphabet, err, c.alphabet)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package internal
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Password rules of a website, in the syntax of the passwordrules attribute proposed by Apple,
+// i.e. "minlength: 8; maxlength: 16; required: lower; required: [-!]; max-consecutive: 2".
+// Only printable ASCII characters without the space are used to generate passwords.
+
+// PasswordRulesType contains the parsed password rules, character sets being sorted strings
+type PasswordRulesType struct {
+	MinLength      int
+	MaxLength      int // 0 if not set
+	MaxConsecutive int // 0 if not set
+	Required       []string
+	Allowed        string
+}
+
+var passwordRulesClasses = []struct {
+	name       string
+	characters string
+}{
+	{"upper", "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+	{"lower", "abcdefghijklmnopqrstuvwxyz"},
+	{"digit", "0123456789"},
+	{"special", "!\"#$%&'()*+,-.:;<=>?@[]^_`{|}~"},
+}
+
+func asciiPrintableCharacters() string {
+	var b []byte
+	for c := byte(33); c <= 126; c++ {
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// characterSetUnion returns the sorted characters contained in any of the sets
+func characterSetUnion(sets ...string) string {
+	var present [128]bool
+	for _, set := range sets {
+		for i := 0; i < len(set); i++ {
+			present[set[i]] = true
+		}
+	}
+	var b []byte
+	for c := range present {
+		if present[c] {
+			b = append(b, byte(c))
+		}
+	}
+	return string(b)
+}
+
+// characterSetWithout returns the characters of the set not contained in excluded
+func characterSetWithout(set, excluded string) string {
+	var b []byte
+	for i := 0; i < len(set); i++ {
+		if !strings.ContainsRune(excluded, rune(set[i])) {
+			b = append(b, set[i])
+		}
+	}
+	return string(b)
+}
+
+// ParsePasswordRules parses password rules, which must not be empty
+func ParsePasswordRules(s string) (rules PasswordRulesType, err error) {
+	var allowed []string
+	minLengthSet := false
+	i := 0
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == ';') {
+			i++
+		}
+		if i == len(s) {
+			break
+		}
+		j := strings.IndexByte(s[i:], ':')
+		if j < 0 {
+			return rules, errors.New("Password rule '" + strings.TrimSpace(s[i:]) + "' has no value")
+		}
+		name := strings.ToLower(strings.TrimSpace(s[i : i+j]))
+		i += j + 1
+		// the value ends at the first semicolon outside of a custom character class
+		start := i
+		for inClass := false; i < len(s) && (inClass || s[i] != ';'); i++ {
+			if s[i] == '[' && !inClass {
+				inClass = true
+			} else if s[i] == ']' && inClass && !(i+1 < len(s) && s[i+1] == ']') {
+				inClass = false
+			}
+		}
+		value := strings.TrimSpace(s[start:i])
+		switch name {
+		case "minlength", "maxlength", "max-consecutive":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return rules, errors.New("Password rule '" + name + "' must be a positive integer, not '" + value + "'")
+			}
+			if name == "minlength" && (!minLengthSet || n > rules.MinLength) {
+				rules.MinLength = n
+				minLengthSet = true
+			} else if name == "maxlength" && (rules.MaxLength == 0 || n < rules.MaxLength) {
+				rules.MaxLength = n
+			} else if name == "max-consecutive" && (rules.MaxConsecutive == 0 || n < rules.MaxConsecutive) {
+				rules.MaxConsecutive = n
+			}
+		case "required", "allowed":
+			set, err := parsePasswordRulesClasses(value)
+			if err != nil {
+				return rules, err
+			}
+			if name == "required" {
+				rules.Required = append(rules.Required, set)
+			} else {
+				allowed = append(allowed, set)
+			}
+		default:
+			return rules, errors.New("Password rule '" + name + "' is not supported")
+		}
+	}
+	if len(allowed) == 0 && len(rules.Required) == 0 {
+		allowed = append(allowed, asciiPrintableCharacters())
+	}
+	rules.Allowed = characterSetUnion(append(allowed, rules.Required...)...)
+	if rules.MaxLength > 0 && rules.MinLength > rules.MaxLength {
+		return rules, errors.New("Password rules minlength " + strconv.Itoa(rules.MinLength) + " is bigger than maxlength " + strconv.Itoa(rules.MaxLength))
+	}
+	return rules, nil
+}
+
+// parsePasswordRulesClasses parses a comma separated list of named and custom character classes
+func parsePasswordRulesClasses(value string) (set string, err error) {
+	var sets []string
+	for i := 0; i < len(value); {
+		switch c := value[i]; {
+		case c == ' ' || c == ',':
+			i++
+		case c == '[':
+			j := i + 1
+			for j < len(value) && (value[j] != ']' || (j+1 < len(value) && value[j+1] == ']')) {
+				j++
+			}
+			if j == len(value) {
+				return "", errors.New("Custom character class '" + value[i:] + "' is not closed")
+			}
+			custom := value[i+1 : j]
+			for k := 0; k < len(custom); k++ {
+				if custom[k] < 33 || custom[k] > 126 {
+					return "", errors.New("Custom character class '" + value[i:j+1] + "' must only contain printable ASCII characters")
+				}
+			}
+			sets = append(sets, custom)
+			i = j + 1
+		default:
+			j := i
+			for j < len(value) && value[j] != ',' && value[j] != ' ' {
+				j++
+			}
+			name := strings.ToLower(value[i:j])
+			found := false
+			if name == "ascii-printable" || name == "unicode" {
+				sets = append(sets, asciiPrintableCharacters())
+				found = true
+			}
+			for _, class := range passwordRulesClasses {
+				if class.name == name {
+					sets = append(sets, class.characters)
+					found = true
+				}
+			}
+			if !found {
+				return "", errors.New("Character class '" + value[i:j] + "' is not supported")
+			}
+			i = j
+		}
+	}
+	set = characterSetUnion(sets...)
+	if set == "" {
+		return "", errors.New("Password rule value '" + value + "' contains no character")
+	}
+	return set, nil
+}
+
+// formatCharacterSet writes the set as named classes followed by a custom class for the other characters
+func formatCharacterSet(set string) string {
+	if set == asciiPrintableCharacters() {
+		return "ascii-printable"
+	}
+	var names []string
+	for _, class := range passwordRulesClasses {
+		if characterSetWithout(class.characters, set) == "" {
+			names = append(names, class.name)
+			set = characterSetWithout(set, class.characters)
+		}
+	}
+	if set != "" {
+		// '-' must be first and ']' last in a custom class
+		custom := characterSetWithout(set, "-]")
+		if strings.Contains(set, "-") {
+			custom = "-" + custom
+		}
+		if strings.Contains(set, "]") {
+			custom += "]"
+		}
+		names = append(names, "["+custom+"]")
+	}
+	return strings.Join(names, ", ")
+}
+
+// String returns the rules in a canonical form, to be stored and compared
+func (rules PasswordRulesType) String() string {
+	var properties []string
+	if rules.MinLength > 0 {
+		properties = append(properties, "minlength: "+strconv.Itoa(rules.MinLength))
+	}
+	if rules.MaxLength > 0 {
+		properties = append(properties, "maxlength: "+strconv.Itoa(rules.MaxLength))
+	}
+	required := make([]string, len(rules.Required))
+	copy(required, rules.Required)
+	sort.Strings(required)
+	for i, set := range required {
+		if i == 0 || set != required[i-1] {
+			properties = append(properties, "required: "+formatCharacterSet(set))
+		}
+	}
+	properties = append(properties, "allowed: "+formatCharacterSet(rules.Allowed))
+	if rules.MaxConsecutive > 0 {
+		properties = append(properties, "max-consecutive: "+strconv.Itoa(rules.MaxConsecutive))
+	}
+	return strings.Join(properties, "; ")
+}
+
+// ClampLength returns the length closest to the given length satisfying the minimum and maximum lengths
+func (rules PasswordRulesType) ClampLength(length int) int {
+	if length < rules.MinLength {
+		length = rules.MinLength
+	}
+	if rules.MaxLength > 0 && length > rules.MaxLength {
+		length = rules.MaxLength
+	}
+	return length
+}
+
+// passwordRulesMaxShuffles limits the shuffles of the character sets of the positions, as some shuffles of
+// required sets of a single character can't satisfy a maximum of identical consecutive characters
+const passwordRulesMaxShuffles = 100
+
+// SatisfyPasswordRules derives a password satisfying the rules without the unallowed characters.
+// Each required set gets at least one character at a random position and all the other characters
+// are drawn from the allowed set, uniformly from a HKDF stream of the identification key and round.
+// With a maximum of identical consecutive characters, a character making the rest of the password
+// impossible to satisfy is drawn again among the characters of its set which keep it possible.
+func SatisfyPasswordRules(passwordDigest *[32]byte, passwordLength int, round uint16, rules PasswordRulesType, unallowedCharacters unallowedCharactersType) (password string, err error) {
+	if rules.ClampLength(passwordLength) != passwordLength {
+		return "", errors.New("The password length " + strconv.Itoa(passwordLength) + " does not satisfy the password rules '" + rules.String() + "'")
+	}
+	unallowed := unallowedCharacters.Serialize()
+	allowed := characterSetWithout(rules.Allowed, unallowed)
+	if allowed == "" {
+		return "", errors.New("No character is allowed by the password rules without the unallowed characters")
+	}
+	var required []string
+	singleRequired := make(map[byte]int)
+	for _, set := range rules.Required {
+		set = characterSetWithout(set, unallowed)
+		if set == "" {
+			return "", errors.New("A required character set of the password rules only contains unallowed characters")
+		}
+		if len(set) == 1 {
+			singleRequired[set[0]]++
+		}
+		required = append(required, set)
+	}
+	if len(required) > passwordLength {
+		return "", errors.New("The password length " + strconv.Itoa(passwordLength) + " is smaller than the " + strconv.Itoa(len(required)) + " required character sets")
+	}
+	if rules.MaxConsecutive > 0 {
+		if len(allowed) == 1 && passwordLength > rules.MaxConsecutive {
+			return "", errors.New("The password rules can't be satisfied with a single allowed character")
+		}
+		for character, count := range singleRequired {
+			if count > rules.MaxConsecutive {
+				return "", errors.New("The character '" + string(character) + "' is required more times than the maximum of " + strconv.Itoa(rules.MaxConsecutive) + " identical consecutive characters")
+			}
+		}
+	}
+	info := []byte(passwordDerivationV4Rules)
+	info = append(info, byte(round>>8), byte(round))
+	stream := newHKDFStream((*passwordDigest)[:], info)
+	if passwordLength == 0 {
+		return "", nil
+	}
+	sets := make([]string, passwordLength)
+	b := make([]byte, passwordLength)
+	for shuffle := 0; shuffle < passwordRulesMaxShuffles; shuffle++ {
+		for i := range sets {
+			sets[i] = allowed
+			if i < len(required) {
+				sets[i] = required[i]
+			}
+		}
+		for i := len(sets) - 1; i > 0; i-- { // Fisher-Yates shuffle
+			j := stream.uniformInt(i + 1)
+			sets[i], sets[j] = sets[j], sets[i]
+		}
+		if rules.MaxConsecutive == 0 {
+			for i, set := range sets {
+				b[i] = set[stream.uniformInt(len(set))]
+			}
+			return string(b), nil
+		}
+		maxRuns := consecutiveRunsFeasibility(sets, rules.MaxConsecutive)
+		run := 0
+		for i, set := range sets {
+			feasible := func(c byte) bool {
+				if i > 0 && c == b[i-1] {
+					return run+1 <= int(maxRuns[i+1][c])
+				}
+				return maxRuns[i+1][c] >= 1
+			}
+			c := set[stream.uniformInt(len(set))]
+			if !feasible(c) {
+				var candidates []byte
+				for j := 0; j < len(set); j++ {
+					if feasible(set[j]) {
+						candidates = append(candidates, set[j])
+					}
+				}
+				if len(candidates) == 0 { // only for the first position of an impossible shuffle
+					break
+				}
+				c = candidates[stream.uniformInt(len(candidates))]
+			}
+			if i > 0 && c == b[i-1] {
+				run++
+			} else {
+				run = 1
+			}
+			b[i] = c
+			if i == len(sets)-1 {
+				return string(b), nil
+			}
+		}
+	}
+	return "", errors.New("The password rules '" + rules.String() + "' can't be satisfied with " + strconv.Itoa(passwordLength) + " characters")
+}
+
+// consecutiveRunsFeasibility returns, for each position i and character c, the longest run of c ending at the
+// position i-1 with which the characters of the sets from the position i can still be drawn without more than
+// max identical consecutive characters, 0 if there is none. A shorter run is always easier to complete.
+func consecutiveRunsFeasibility(sets []string, max int) (maxRuns [][256]int16) {
+	if max > len(sets) {
+		max = len(sets)
+	}
+	maxRuns = make([][256]int16, len(sets)+1)
+	for c := range maxRuns[len(sets)] {
+		maxRuns[len(sets)][c] = int16(max)
+	}
+	for i := len(sets) - 1; i >= 0; i-- {
+		var inSet [256]bool
+		startable, last := 0, -1 // characters of the set starting a new run which can be completed
+		for j := 0; j < len(sets[i]); j++ {
+			c := sets[i][j]
+			if !inSet[c] && maxRuns[i+1][c] >= 1 {
+				startable++
+				last = int(c)
+			}
+			inSet[c] = true
+		}
+		for c := range maxRuns[i] {
+			switch {
+			case startable >= 2 || (startable == 1 && last != c):
+				maxRuns[i][c] = int16(max) // another character than c can be drawn
+			case inSet[c] && maxRuns[i+1][c] >= 2:
+				maxRuns[i][c] = maxRuns[i+1][c] - 1 // c has to be drawn again
+			default:
+				maxRuns[i][c] = 0
+			}
+		}
+	}
+	return maxRuns
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePasswordRules(t *testing.T) {
+	cases := []struct {
+		rules     string
+		canonical string
+		err       string
+	}{
+		{"minlength: 8", "minlength: 8; allowed: ascii-printable", ""},
+		{"minlength: 8; maxlength: 16; required: lower; required: [-!]; max-consecutive: 2",
+			"minlength: 8; maxlength: 16; required: [-!]; required: lower; allowed: lower, [-!]; max-consecutive: 2", ""},
+		{"required: upper, lower; required: digit; allowed: [-]", "required: digit; required: upper, lower; allowed: upper, lower, digit, [-]", ""},
+		{"MINLENGTH: 6; minlength: 10; maxlength: 20; maxlength: 12;", "minlength: 10; maxlength: 12; allowed: ascii-printable", ""},
+		{"allowed: [bca]]", "allowed: [abc]]", ""},
+		{"allowed: [;]; required: digit", "required: digit; allowed: digit, [;]", ""},
+		{"minlength: 0", "", "Password rule 'minlength' must be a positive integer, not '0'"},
+		{"minlength: 20; maxlength: 8", "", "Password rules minlength 20 is bigger than maxlength 8"},
+		{"required: emoji", "", "Character class 'emoji' is not supported"},
+		{"required: [abc", "", "Custom character class '[abc' is not closed"},
+		{"passwordlength: 8", "", "Password rule 'passwordlength' is not supported"},
+		{"minlength", "", "Password rule 'minlength' has no value"},
+	}
+	for _, c := range cases {
+		rules, err := ParsePasswordRules(c.rules)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("ParsePasswordRules(%q) error == %v want %q", c.rules, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePasswordRules(%q) - %s", c.rules, err)
+			continue
+		}
+		canonical := rules.String()
+		if canonical != c.canonical {
+			t.Errorf("ParsePasswordRules(%q).String() == %q want %q", c.rules, canonical, c.canonical)
+		}
+		reparsed, err := ParsePasswordRules(canonical)
+		if err != nil || reparsed.String() != canonical {
+			t.Errorf("ParsePasswordRules(%q).String() == %q want %q", canonical, reparsed.String(), canonical)
+		}
+	}
+}
+
+func TestPasswordRulesClampLength(t *testing.T) {
+	rules, _ := ParsePasswordRules("minlength: 8; maxlength: 16")
+	cases := []struct{ length, clamped int }{{4, 8}, {8, 8}, {12, 12}, {16, 16}, {30, 16}}
+	for _, c := range cases {
+		if out := rules.ClampLength(c.length); out != c.clamped {
+			t.Errorf("ClampLength(%d) == %d want %d", c.length, out, c.clamped)
+		}
+	}
+}
+
+func TestSatisfyPasswordRules(t *testing.T) {
+	seed := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	rules, err := ParsePasswordRules("minlength: 8; maxlength: 16; required: lower; required: digit; required: [-!]; allowed: upper; max-consecutive: 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unallowed := BuildUnallowedCharacters(NewCharacterConstraints(false, false, false, false, "!"))
+	for round := uint16(1); round <= 20; round++ {
+		digest := MakePasswordDigest(&seed, "website", "user", 4)
+		password, err := SatisfyPasswordRules(digest, 12, round, rules, unallowed)
+		if err != nil {
+			t.Fatalf("SatisfyPasswordRules() - %s", err)
+		}
+		again, _ := SatisfyPasswordRules(digest, 12, round, rules, unallowed)
+		if password != again {
+			t.Errorf("SatisfyPasswordRules() is not deterministic: %q and %q", password, again)
+		}
+		if len(password) != 12 {
+			t.Errorf("SatisfyPasswordRules() returned %q of length %d want 12", password, len(password))
+		}
+		if !strings.ContainsAny(password, "abcdefghijklmnopqrstuvwxyz") || !strings.ContainsAny(password, "0123456789") || !strings.Contains(password, "-") {
+			t.Errorf("SatisfyPasswordRules() returned %q missing a required character", password)
+		}
+		if characterSetWithout(password, rules.Allowed) != "" || strings.Contains(password, "!") {
+			t.Errorf("SatisfyPasswordRules() returned %q with characters not allowed", password)
+		}
+		if maxConsecutive([]byte(password)) > 1 {
+			t.Errorf("SatisfyPasswordRules() returned %q with identical consecutive characters", password)
+		}
+	}
+}
+
+func TestSatisfyPasswordRulesErrors(t *testing.T) {
+	one := []byte{1}
+	digest := MakePasswordDigest(&one, "website", "user", 4)
+	none := BuildUnallowedCharacters(NewCharacterConstraints(false, false, false, false, ""))
+	cases := []struct {
+		rules     string
+		length    int
+		unallowed unallowedCharactersType
+	}{
+		{"maxlength: 8", 10, none},
+		{"required: digit", 10, BuildUnallowedCharacters(NewCharacterConstraints(false, true, false, false, ""))},
+		{"required: lower; required: upper; required: digit", 2, none},
+		{"allowed: [a]; max-consecutive: 2", 5, none},
+		{"required: [a]; required: [a]; allowed: [ab]; max-consecutive: 1", 5, none},
+		{"allowed: [ab]", 5, BuildUnallowedCharacters(NewCharacterConstraints(false, false, false, false, "ab"))},
+	}
+	for _, c := range cases {
+		rules, err := ParsePasswordRules(c.rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = SatisfyPasswordRules(digest, c.length, 1, rules, c.unallowed)
+		if err == nil {
+			t.Errorf("SatisfyPasswordRules(%q, %d) should fail", c.rules, c.length)
+		}
+	}
+}
+
+func TestSatisfyPasswordRulesMaxConsecutive(t *testing.T) {
+	one := []byte{1}
+	none := BuildUnallowedCharacters(NewCharacterConstraints(false, false, false, false, ""))
+	cases := []struct {
+		rules  string
+		length int
+	}{
+		{"allowed: [ab]; max-consecutive: 1", 40},
+		{"allowed: [ab]; max-consecutive: 1", 4096},
+		{"allowed: [abc]; max-consecutive: 1", 64},
+		{"required: [a]; required: [b]; allowed: [ab]; max-consecutive: 1", 3},
+		{"required: [a]; allowed: [ab]; max-consecutive: 2", 40},
+		{"allowed: [ab]; max-consecutive: 2", 100},
+	}
+	for _, c := range cases {
+		rules, err := ParsePasswordRules(c.rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for round := uint16(1); round <= 20; round++ {
+			digest := MakePasswordDigest(&one, "website", "user", 4)
+			password, err := SatisfyPasswordRules(digest, c.length, round, rules, none)
+			if err != nil {
+				t.Fatalf("SatisfyPasswordRules(%q, %d, %d) - %s", c.rules, c.length, round, err)
+			}
+			if len(password) != c.length || maxConsecutive([]byte(password)) > rules.MaxConsecutive {
+				t.Fatalf("SatisfyPasswordRules(%q, %d, %d) == %q", c.rules, c.length, round, password)
+			}
+			for _, set := range rules.Required {
+				if !strings.ContainsAny(password, set) {
+					t.Fatalf("SatisfyPasswordRules(%q, %d, %d) == %q misses a required character", c.rules, c.length, round, password)
+				}
+			}
+		}
+	}
+}
+
+func Test_maxConsecutive(t *testing.T) {
+	cases := map[string]int{"": 0, "a": 1, "abc": 1, "aab": 2, "abbbcc": 3}
+	for s, expected := range cases {
+		if out := maxConsecutive([]byte(s)); out != expected {
+			t.Errorf("maxConsecutive(%q) == %d want %d", s, out, expected)
+		}
+	}
+}
+
+// maxConsecutive returns the maximum number of identical consecutive characters
+func maxConsecutive(b []byte) (max int) {
+	for i, n := 0, 0; i < len(b); i++ {
+		if i > 0 && b[i] == b[i-1] {
+			n++
+		} else {
+			n = 1
+		}
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
@@ -0,0 +1,211 @@
+package internal
+
+// Known answer vectors of the password derivation, frozen for every version so that
+// a change of the code or of the Go toolchain can never silently alter existing passwords.
+// Vectors are only ever added, with a new PasswordVectorsVersion, and never modified.
+
+// PasswordVectorsVersion is the version of the corpus of password derivation vectors
+const PasswordVectorsVersion = 3
+
+type passwordVectorType struct {
+	passwordDerivationVersion uint16
+	seed                      string // hexadecimal
+	website                   string
+	user                      string
+	passwordLength            uint8
+	round                     uint16
+	unallowedCharacters       string // as stored in the database
+	password                  string
+}
+
+var passwordVectors = []passwordVectorType{
+	// Version 1
+	{1, "11050255b2ff001d", "google", "a@a", 1, 1, "", "b"},
+	{1, "11050255b2ff001d", "google", "a@a", 20, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "VbgRf49606wENC9xFhnE"},
+	{1, "11050255b2ff001d", "facebook", "", 4, 2, "0123456789", "K[ns"},
+	{1, "11050255b2ff001d", "facebook", "", 64, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "(~;3+7=/460!89;9953,185%6\"+(!\\86%>#_6%87-2|5269`\\7|}!:90*@23/86;"},
+	{1, "11050255b2ff001d", "ab", "c", 13, 7, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "AJKIBKXMOAQUB"},
+	{1, "11050255b2ff001d", "ab", "c", 128, 1, "lOI01|`'\"", "rG!;A%9z7[@?57B6:8iQ6r7xfipHWSQD,d,Af4R(VR@(CFL*H}DhB;7t&xp8oy>;HP&4&cozBog2=g432Dr%8779]%q7d]US2(448]uEF566?pUd2QD<y85Qmbw(]X2]"},
+	{1, "11050255b2ff001d", "a", "bc", 20, 1, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "llWC7Q0h51b6tVZ4kJDt"},
+	{1, "11050255b2ff001d", "a", "bc", 255, 2, "", "r300z9#;&@Cm0C4R8A<6fl20$7t5E2z1GX|,0XP!;U0-HJ_4).UkfG!Gcl\\2KC!K~Sl$RCoYr&n/4]gWCp3v:tHw*@N!!+M'#s5r18fIOMM[cgm398d5W3Bn^70~8K.#`(*}Hx?72Mz2]C56M1*IetC_YAsA5s/QFkUhs0Hw6GxBnWd3'k4s:hTh{qZf5>dR1#E<7\\al91r!c[205Yd,29\"F96m6T6oG,~6:P^T}KE350fmTy70&,a4mIg$xnx6"},
+	{1, "11050255b2ff001d", "example.com", "john.doe@example.com", 64, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "L4Gtpf4qlj3uuC99vCC9fE4B05BMam0x3D3QPPvQL01iL7WW4sJHu6cpg96sK90j"},
+	{1, "11050255b2ff001d", "example.com", "john.doe@example.com", 1, 7, "0123456789", "j"},
+	{1, "11050255b2ff001d", "π-site", "ユーザー", 128, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", ">}04286?8!4|/>2)~'.8}1`];4<=0%/6!65>`992>3'``6$\"[@4<411\\?1`71%_[\\&5,+0_3]06*7-'!9~=87<9`3(:7#9238<?{4650|6559,~30_1!^7580976+388"},
+	{1, "11050255b2ff001d", "π-site", "ユーザー", 4, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "OJZA"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 255, 1, "lOI01|`'\"", "ry(Lw2^zjFKk7oc3M=MU!QBP4p$[6$F}@j^7+8J6w?Zzs27R5tg:.7G47VJ^9X/4cS^N4$gfGUm8}zJH~;5-DZRCEit9d%r5#y{$kUE9Tr5JfF85<n4N-D)u:5h$sj;8*79r%2eX&@8V6RS83r.2s93Kh*UTJ^gH7J7%dwooJpJs3U4NkhN:X?[%8k)7K29*$EV9t:95bt)8)R4M2G8\\p2eqA5-P9,_o2_r8w3s3kyCB=BMz.%5o8=>#4>7Sw{b"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 13, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "y8jB4C9DzbH3k"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 1, 2, "", "n"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 20, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "PB0Y7d3MmOsQ7fkbI1m9"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 4, 7, "0123456789", "h\\Tc"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 64, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "7{'>6539908;!95>>~69^01)738~2``_{492{5<1;\\6=6!9%!;2(,1'!92+8=4@?"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 13, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "CUOBYIOPFPPYP"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 128, 2, "lOI01|`'\"", "ofYM4T3UF9m6evCZm+*7sqm$RFu2~trJ.Ry/dk*T}^US(ZvjP2b89W7dwh4A.j2-385/3sM29j;<wG5<H>fMV$3]#)@C9RFK/}374Zr^i6M)(eN%4a,t9!F8+T89.w78"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 20, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "B1CE6fZJbk34sAp03rnJ"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 255, 7, "", "u{R&C[w=CK_:OW16a1P[_ne7zSu817VK0Kt&LH*N848EZ/$4:9pkxmr37k;ef>5{<K]%:q*Fo)3kP{NdfGU\"t12GD+a3Oh,5:F2;{i0uw2nf928#+h0VKH?N2RE/Wyb6AoK934c#zU9671#@7Tt{5E@Qv'60ag6b!X7`&1|Scd7Wg:9pI&]6XN74Mov]j#2!rYZ7v3!ucJ2J{-9{szWuHYQJ4XFq'?n);Og23`Ly6P1b;{52u@A_16/tAKrvG8z"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 64, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "PzmqoR3qFLkcG90P8fh5bzv4Y49SEBIzUoi8N9SQ5rXUgZ8v822n09cG3CVe2s81"},
+	{1, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 1, 1, "0123456789", "v"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 128, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "|9!927<193%(;0780(>/47:606<'-06-.9.&8+#13{3>>814!57'679?<|6|7'`9'3/19}439:295=%*?+-96':69,%`04<0/73&;076_~69-^5\":1-=4-7888}^}:!]"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 4, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "XZPI"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 255, 2, "lOI01|`'\"", "X85i4c%[-8NP93q?3S3(ewS2wm4j;s8:*{]?oS>n_pA7YF5xe9q4aK>Hs(Bx7]+(~#2*PQDQ^4Un84z6<{rg*Q7MFuB3)j>Gaz_w&)%89QVdAwftSJzCF-h@7HobW77h>3Ah{Y{~YT5X=f<TR~cUb=[UC2j*h7+4+4RhbE$374SH?x>Zag97S8cxo8{493oD4~8?$N[*Aq2Xc5N2w2F2V9=-!9r47b$x2YH7{JEyf46CV8Pg[9m~7UT:6hZw.w9"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 13, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "ByMkqn202Rt2G"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 1, 7, "", "k"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 20, 1, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "efK9MIl03tsO4V3Wwv5D"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 4, 1, "0123456789", "Nox]"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 64, 2, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "80{3@)09189-&%~+/`]_86!${,<:!$165|52271}02;755+29`[1..3\\81$#1+15"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 13, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "MPVSQHVCNDWGE"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 128, 7, "lOI01|`'\"", "R346K=px6gW55BE6@Eo8!8[BtXv8h},4THiTSmr<rA+Q5S+2%3Q\\44UxfeKA[!B-L2*6(NrYb8Rh6S^=>?L7a<6f8g_R8XoLfY%#8uL8^6h)&5u7/+6m?99]ni_jiwXk"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 20, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "OhT1I0AO4X1nmhw7Hj8b"},
+	{1, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 255, 1, "", "Ej]]>$45yze,ZjC\"4S45J}IjG.GDET3[0kO5=ZzIW%>52&pjN~~7yJ<jVdC@+T4Z0]nRqLvldp3031s3cd;swiB_K#6SRN,63m:SimesAB>P;1h*'ddZnr]J*2\\+br~$3}@1*uew~9~A/,9bW}14`4v0}CQNq`yciMKo3[2Q09G#HNy0E'K7:l39!299RL8so4l4|Hwj;[6%O$h79wpE8M&0Q93SCG-d74uLm\"35C/h14e7ej@E/D834+0@HHR6"},
+	// Version 2
+	{2, "11050255b2ff001d", "google", "a@a", 1, 1, "", "z"},
+	{2, "11050255b2ff001d", "google", "a@a", 20, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "K230WooZKUttB7s6Aew0"},
+	{2, "11050255b2ff001d", "facebook", "", 4, 2, "0123456789", "K[ns"},
+	{2, "11050255b2ff001d", "facebook", "", 64, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "(~;3+7=/460!89;9953,185%6\"+(!\\86%>#_6%87-2|5269`\\7|}!:90*@23/86;"},
+	{2, "11050255b2ff001d", "ab", "c", 13, 7, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "ROECVGXGMXPZM"},
+	{2, "11050255b2ff001d", "ab", "c", 128, 1, "lOI01|`'\"", "W8mK)YtKa.t>YWK%oeN2m2&T9g4?3v%62$S{}4*7]Hm!;TFjRcXbG^7H32[4\\n24k8S6fV8hAya9+>}RP]4bYS54Vaf_Z\\eBu[Rg.NQ9599u6#ymCz(b/%95{{(b6x8D"},
+	{2, "11050255b2ff001d", "a", "bc", 20, 1, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "P552C1WV1ffVqOvgfmZ7"},
+	{2, "11050255b2ff001d", "a", "bc", 255, 2, "", "JGd3*Th45]8=@`rd'M13T5jQh\"&jg1f@e0:R=t`v4Z_4*R+\\+-(~0W_rLCO=7VA3Pu55^?4+d52k@n74]50N%AOZl_6+r0>ko&]8pa}~12IkX+~911S7hAj!N6O`t~0/59pI_?02qwjd'8rZ8o1pAG.YmLo,#r.1J&^Tr0JdL3JK289mCkKf>uN<Sw]:KcH5dg7_vjcOG2,BQ!BO.3kzBF/64S3bF<X31mxowuluV31QUiOm7P-T2V0669Df^Q~"},
+	{2, "11050255b2ff001d", "example.com", "john.doe@example.com", 64, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "E29DxQBy0Rpa7Ean2gO7f4E1cJd2aBZ2qW898wTF1VdTTw6lrb6zQ42Bx1wWq0N8"},
+	{2, "11050255b2ff001d", "example.com", "john.doe@example.com", 1, 7, "0123456789", "i"},
+	{2, "11050255b2ff001d", "π-site", "ユーザー", 128, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "0153>/#7\"5)$5!\"-02'5_81'70>7+.8=*80+3$-3\\:2(5*/+@4\"-{0[.}:6{}0;6@@26~\\>9~[~50$280;&~67#52}~,2#/04+519<323487=@372965$9<\"61_1@329"},
+	{2, "11050255b2ff001d", "π-site", "ユーザー", 4, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "EWZU"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 255, 1, "lOI01|`'\"", "U4rXcruAVMqT5Q!+32JJ4Zxt~499F4+8Qx#j_5vk83st*F:3~F,U_9e6bqB+f4vbTHZgvjkv8o3z;!\\7L;G24P(u9-{raW7xUYijn}3MJ_z#p+@\\uh69aA7w66K-MyKM9/%<*MZyAz9$2qzH\\7y\\ES7h93=zL;~<R6,8+YK(4_H>iNB*88GnSX7p74_5,3TL^t~=7LY;8@~}Ha5LF4\\3KA?z9*/Zka+E_@\\~omy6?f9@w8fV63w963G48mCD5Lg"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 13, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "c8jmUcRz7PL21"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 1, 2, "", "n"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 20, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "PB0Y7d3MmOsQ7fkbI1m9"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 4, 7, "0123456789", "ch,P"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 64, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "^^7#1#@$25(2[!133{$~=121'869|4=2<5|904}5!.;`+%9<3('?79>6,488301("},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 13, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "SRQHAXZITCFBF"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 128, 2, "lOI01|`'\"", "PpS5S4)ds)f(H/6K9:29a8)AVn,Fio)a!9[*%\\eb:98J~WFg]8##E4Vib2aMZXu[Xy3+2f{sXZhg5E5pwkqJ4UjR6u#7~6QK4:9<8DXEb-_8c-55}3k!569TDG?ww}S8"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 20, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "SJAt1Pv63gm4Ykkq51KE"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 255, 7, "", "aO3d1~7Kwt76b?VQ>]:(29FOqFY`pmu}'#Qsa-J.)35iH887Yl^(`pT)Wu`3Ulx0&03lE\"z4<-Xi'v\"!7\"6Pm$R6xL1,g9R7AM0pT-zy19hD#rcS:Q5L^;UaDU28n9AW3+f04k6&PJr@1%V7Cb=!5TA6Zv{3[88U]8Sayx3;0Kri5LdbT}7VP6hs:adBk>$M4D{YeVt295Mx75?XiSX'}E1ydt^a6.+h+xz94e-91t+I2|xCC]=9$GX7^{80ZeX"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 64, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "I3ln2P641yQM3MK1mcnCgX59Xt3acW18Qs8JBwy91CI0wjwfA068fHmlRZnV0A5w"},
+	{2, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 1, 1, "0123456789", "f"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 128, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", ",+265!;^63%6[87961^&365<3|?506<'[67)]3'}28;)[5712233;,#,[35~;\"9>-7914]6\\49;&713*7=10^|1459+((,71[`51+??#;4(%{]6_8$/271^6^1}-#9!9"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 4, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "XHII"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 255, 2, "lOI01|`'\"", "X85i4c%[-8NP93q?3S3(ewS2wm4j;s8:*{]?oS>n_pA7YF5xe9q4aK>Hs(Bx7]+(~#2*PQDQ^4Un84z6<{rg*Q7MFuB3)j>Gaz_w&)%89QVdAwftSJzCF-h@7HobW77h>3Ah{Y{~YT5X=f<TR~cUb=[UC2j*h7+4+4RhbE$374SH?x>Zag97S8cxo8{493oD4~8?$N[*Aq2Xc5N2w2F2V9=-!9r47b$x2YH7{JEyf46CV8Pg[9m~7UT:6hZw.w9"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 13, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "ByMkqn202Rt2G"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 1, 7, "", "i"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 20, 1, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "Id3bcZYKj6633auOIR6h"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 4, 1, "0123456789", "e)fE"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 64, 2, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "[575\\:;4\\5^2&=9=8%80]$2}5423$_#&.360]}1!5#8\"-6;)>.47[_379>63386'"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 13, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "ARKYMQTNJDPFJ"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 128, 7, "lOI01|`'\"", "xZ=5m>Hba9US&2Am63$*+N4a95yhFt9m7SnvEs(L[C49{,Z25+49G4gyP!w+_w78H[_G,g9H6).3]9TStP(}55/s4t^VW4<ebBs/gmjt44KBU#$9QL2Psb\\4ZFf*=!dT"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 20, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "y6OwtVw00rbS47gAM8NJ"},
+	{2, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 255, 1, "", "h4`M(D_4MS?'i881P.6{75MKfn(I[M8\"B}xR{N[w4V,9RF9EggTI=8GN8aR<6\"x0%0IU}7wtmFQbDh4d00-:5t6#@(=~c83.UP9?jYbwa3L`KW<&zS1FaxB42*L39Y9Ib8uWR'b<V83v7pC3fFx@fo9#}4YX6?92wyR!jE86w`prrx7'Jtme^z+zDG_\\0255>oN7pb3{'yfAst|36]nL9ENyK]0='SLsN2kEeI_4^:I29,n775?rD,Tq=g,z':9"},
+	// Version 3
+	{3, "11050255b2ff001d", "google", "a@a", 1, 1, "", "r"},
+	{3, "11050255b2ff001d", "google", "a@a", 20, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "K4vV4QpdKq85Dbl9QV5f"},
+	{3, "11050255b2ff001d", "facebook", "", 4, 2, "0123456789", "Zm/m"},
+	{3, "11050255b2ff001d", "facebook", "", 64, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "[2;<13741\\-'2%|+3<5~{#5617/64#0|3,009,853}#)74`63^8:/79]1\\1{~$>|"},
+	{3, "11050255b2ff001d", "ab", "c", 13, 7, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "GQTYVLERQVWIT"},
+	{3, "11050255b2ff001d", "ab", "c", 128, 1, "lOI01|`'\"", "hV(ao6vF8z+\\Z{X<;4k8xM;Ykg7*&8TZ7S3e\\~^m*-<5yRC5dW(7k72!7[y$NPQ3CANa%Uz9qw]8D56Vgkz?k<67gz6Ym{,RK/272Jg9&2Ne^Sy/c+Lh9k9&986CT>QT"},
+	{3, "11050255b2ff001d", "a", "bc", 20, 1, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "Jp3xCz43ZJ9wFXIcrc18"},
+	{3, "11050255b2ff001d", "a", "bc", 255, 2, "", "G8_46B|(bbJpJ]scsnO89rpm%5kjSbt$hcB54v\"T#,5|8UEVb8N.T[8.9s7b-91a5'4:S;F\\U`07-#>0$|B71xN0Vu3>5s9169Vr}0y&Vd`2'3U5p3c6hw3h2DCUNRk/Zn4EtfG5yX@(fPaQ.4Gd:-sH590mLEB8h}+m5?6nVVNV?9oHS3cAHC/bs355sqlybd#37L'FBFTS-5N8\\\\'if3R\\w]alB`}1.[aA:u=K%\"*BG<8`#XbB744k?37MA<*"},
+	{3, "11050255b2ff001d", "example.com", "john.doe@example.com", 64, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "Ga0PvWr4UP1rPbZTATku0l07kIGpl93cHY6677p0wLc8O85tIlXK5620Yc4viLok"},
+	{3, "11050255b2ff001d", "example.com", "john.doe@example.com", 1, 7, "0123456789", "r"},
+	{3, "11050255b2ff001d", "π-site", "ユーザー", 128, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "\"+)515}0>)${197_`(~?%16+5\"!1181:*3*+7})34=[<.,+9792'*\"'9003~{{794~6`;431@47#6@29}9}^71\\713$+26^6418\"@9-?-!>4^29@960^[09029^29'}$"},
+	{3, "11050255b2ff001d", "π-site", "ユーザー", 4, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "QUMW"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 255, 1, "lOI01|`'\"", "m52xnA2A_5(.eH2sv9_dE:V6To_>Z9i54qe&dQZ.c\\Y>cE77c694m56deP+/E86nBcG99#jHbq6hwMY7W3$J))B\\_8&(F2vtf7)243?z}JN$Cd5.e:kHzBCB7%Xz9$u2A2wMMK35/f%:BCh8tvER5nKKHD:4v^[(5?GUF6!g).2>rQ{n8N4v,uLd4#x63[QG)2p8PK>p*Un5bX+uW4E6K;5h>Va4.h3>,!Tf5{(99nZ7+N7tY86><Sxpj8C)#\\t"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 13, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "L6v7qfJ6Q3Rjv"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 1, 2, "", "y"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 20, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "a0U9Kzd77XsTSU1bN5mq"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 4, 7, "0123456789", "r_iP"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 64, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "6{'3$&1146|2\"8&&7%2~5#4{}*24{&0-$./4431919_:,+8):9747,25)#/8,'00"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 13, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "SRBNKSOHFZKOA"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 128, 2, "lOI01|`'\"", "ZPw9>N#Udg6\\:9),<27D8}5P5e,V)N824)LZUm,9QZi^NqhCt2LD~gy4,d96Rn78![eJ4)+u)/5dJD6%f\\5be7HDkj9U{tB-8dCrf356Y!\\53&v._DeuAeTk[2Kf4]dZ"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 20, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "Jd0j0Nf0Vn8WDrqb8G7G"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 255, 7, "", "s6+Y2^lsf>8o7Nu#5pA2*^2gtZ0:zF3|AB62e35a9mU6[fZ,\\sZvcM\\/Sy7f788:F\\NZd32r+1+Fog3NA_E9|i{R<QR7.\\7AmHxt@zg^3{3tPK\\Dc-O:m9D7729WK40Et](NY`\"a5SY=]sE;U~,2B'W57=22k;2TH_wO{g2K#%1NQr9Xi54-[17AH1Se7xhIs2kWdLGocoi(KLCZ;z)m6piq?173A3^f8N7'jhi5N<9/&~Ffl2le|$^n^V5?&H9"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 64, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "I1pOPvcHUx2lZeSoN91BIF9zf035o6NBp7oHK47Q6270jw8Djil9jDT58zUTgxf0"},
+	{3, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 1, 1, "0123456789", "p"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 128, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "4&<]]<;\"5:8:55!-4}9\\$`7^#1)!@<}429654_0:6<4(4`5)>78&2|775@$(9(\"@36?05\"`0${('346700;17)@2&=^\\`>6-0&+~08312}788%:70{26@8)598244[75"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 4, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "GHLO"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 255, 2, "lOI01|`'\"", "p2mar.C2CA9hF-DumjV?o7]k,3.5hU[QQ,e83442d;9Rj8j\\FAMBbuBH2K2Ux4ttfcF6]R;+8{#9>3~<44mM4V<=~9%B^8j5/8Qzh3Y5i$.m?9$7#WvVx8W>L8h_FtKo+(>d2<2,98(NBo@6asyV5ct+\\g+3]jBmF3dvPmE89&R3$m7+:k9CVE],w?c(4&LV+FQd#.362#5ouBU9/PPMP2AS]8iS>M<#bx4v476Z7mRRa47@NFs$xcT3yA2H%3f"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 13, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "kFjv35V5O4tHy"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 1, 7, "", "i"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 20, 1, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "nn5Oo0M4jnFJX3wI37tM"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 4, 1, "0123456789", "xA?p"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 64, 2, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "78|128921*\"}\\5415>\"7@^)3#30^(598'_9+1-{_#1*]0296*$3474|~4.%2(,_."},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 13, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "ZRICFAQVDRWWF"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 128, 7, "lOI01|`'\"", "NpnT>e7Y_4![f4zc53W{3g7bN/dpnia8bhfD]NB\\~7q3-h(DY=DEg]7S9UAyK6E3b\\h}Gw426,P64yJ687&tn2KCP?8zGQ6v6Y9:7XV&t[^\\wAq%[/V}{947L(6[$;Lz"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 20, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "Ib9J4WfQt4yBz3Eg8V5y"},
+	{3, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 255, 1, "", "bs`Z4Y#hM+M6wyS39;tYUS!\"]I\"bY*f74k25nn\\7q^6rPxan0YE%H{/2Th\\o83<+~TRcKP\">SL{[lx3O</567xf-C?b=9\"5]3?9vTuC1&6nyP&1MKg9zdVh0l1HT4b\"7k5z(K5;l\"c&HP90g!Y8Z\"s841v2#l3Ng}qVb09l)82cUS5'85>qT5?N1MAV6j~YaUDBT=(!/0EV[l'P9NLp/X3{sx^w:x7{zE6[N7*0h2rE_\\n78Ht7U2-qgRC]56Cg"},
+	// Version 4
+	{4, "11050255b2ff001d", "google", "a@a", 1, 1, "", "i"},
+	{4, "11050255b2ff001d", "google", "a@a", 20, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "rkwz6IKT26id9MpLY7K4"},
+	{4, "11050255b2ff001d", "facebook", "", 4, 2, "0123456789", "y[Du"},
+	{4, "11050255b2ff001d", "facebook", "", 64, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "/\\,8|4569220./];6^4.89771?-5&)}7~6/$.1?39832@=7'3{#:88-59:7?&1!!"},
+	{4, "11050255b2ff001d", "ab", "c", 13, 7, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "HJFDDYFQFQOUJ"},
+	{4, "11050255b2ff001d", "ab", "c", 128, 1, "lOI01|`'\"", "RKK2FdsFh,53P5cU&-_946g5P}}+VRY}nhW7L4u@6x,WV7C9Drohwev4x#;tM652;B{D?^R#2Z5]Bb/+U9%hG3xrgMfeD9+7=489D-8tqoBu43bj;\\8m7YVy]B:&-=6}"},
+	{4, "11050255b2ff001d", "a", "bc", 20, 1, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "Twk50Lj1MtWI94qfD6jB"},
+	{4, "11050255b2ff001d", "a", "bc", 255, 2, "", "EVG7Q-8M/ITz1b80dsd'~Q4r4C\\S@IJRKfXL38[23F0oChlc8sSYn^0;4O8O/33YMZq'>1DJhm&~n1~DL)2?r6jp<<\"{~SfL>K4~^r7\\x>bt986U40.44o6)\\9}.9*6:vY_Y(t~~PFXN}%T1{CgH6R57xkw(\"}<~p7gVDG2E9wTaxodZsn4*=42r4SK93Kel|ki|dfX5Y^79p(JW1'57:&AdXugA1lr+k%~urio3~]cx2>3QSs3O816V7wXo1/`"},
+	{4, "11050255b2ff001d", "example.com", "john.doe@example.com", 64, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "LDEepBo5DdnIWcvrmsJ6g8Su1724cu7xH55mGo9LFAM613ov7XR1SrL3T76gN30z"},
+	{4, "11050255b2ff001d", "example.com", "john.doe@example.com", 1, 7, "0123456789", "i"},
+	{4, "11050255b2ff001d", "π-site", "ユーザー", 128, 7, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "%7%#\\:697<70*5=:3)5^2'#=2|5`%@1-13794556|;03/{2*?76=(487<(99$\\:374\\8?75)9.5=.8409|06{6?%3=#*68924[71&3**`!7$3#%^8-!950.@1&`7.<4@"},
+	{4, "11050255b2ff001d", "π-site", "ユーザー", 4, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "GJHO"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 255, 1, "lOI01|`'\"", "H,SU&8mZ;;3TY$9nGEVpyy#7h25s#4.7Tv3,9HsQu\\9Y8me;Rc5TW\\>{v#-ox>N3A##659}!-?h7H{EsWX^6ZzB4ee5&;LNTq{26R4%x@~_c,48QTQbK2<t5$g7Cn79t?h}t@tfm6Xzf.FvJ$26eY~hi[8)j3twwtB;887q6;jVKn/N82v24\\?Jn92=^5)AEZ83*4q8!RFaXryPM5nb=2PT9VWRM-dn2>88a]58oXH7\\>M}EDd2Sasv9^}]7GV9"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "google", "a@a", 13, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "f56YdTS7Edyn5"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 1, 2, "", "j"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "facebook", "", 20, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "AfPd586GfmDeO9Nl7Il7"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 4, 7, "0123456789", "yq/X"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "ab", "c", 64, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "|1421!<6_@?1[32/!303.+11)=0@38912&8~<|1*;6[[05/675{7>.,>5:842-++"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 13, 1, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "VUSOMHEEXXHQS"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "a", "bc", 128, 2, "lOI01|`'\"", "ix9nBK&+k7Ki8,46E^S5&Nks34(Skn2aX6)mgs[]46u6$5jBF2D4$7ZCx6/N]8<_74*+>D6s:-:7Lzy7&n2AD3KPJ=^^{,+Vu2eVbf6BH&k83JpZTp}}5w3svn\\SAAuZ"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 20, 2, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "rlCWXq5k8pVFSVwt2750"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "example.com", "john.doe@example.com", 255, 7, "", "e@bPV992w,fzTw}s96.{{aG!(7Sx,}2p5t'6c09:74F@Z;dI^i}9`+4upWCb16,Ne}.8<:O2MdC!_fibMP.vG2Tkvw2qO4dg{gHi3}bZ4NX4>7vDhPkXeh6R?4&8=ttpoDKN/rN4--PM\"QUSoYK/\"[7Zi>2y2_3Km8!;UmUs4(0;)Hf&,doR#65c3811v~WU5Y)i9W2`]N9<VK5xz(^m2J848rllqQ-vORJ8FA8SQ>0'1G98^n!9|8G69p\\R3C1"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 64, 7, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "etJl4tcpDUicDIf58DK1nX299By9PbcYlu69l0P0eJhZEMn5x8303nwW60JVVI49"},
+	{4, "44aa52219e57a3591b0032ae4937936b8960a42bbb14e213f052599f8c0a3850a5403bdba6953759fb9d7399baa73e05c4ddb53241d3a25e35d14f4d5b9aa446", "π-site", "ユーザー", 1, 1, "0123456789", "h"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 128, 1, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "2!330#8{#'57]0&}0326?!!857.[206-74497|*2.%7`[.0[7{<35[416[238`(&$2\"67!/727;{04\"\"1'4*@5\"1-718<:0%]`=>540/222(2\\56)@^<?&=^#861-;]4"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "google", "a@a", 4, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "SDIQ"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 255, 2, "lOI01|`'\"", "khYS&@oHLx*b79cQ4&24E7ian)2A77<.T9[uf#q]AMdB55Eo28LeEgo46kRL~CYV<%4*954dwLCrZA3J,;d/5+p2o2#i_9j}7:n83!YqJ;j2QN73_6/8[~r3g_M9-9^a*8SaB/5K@X9XU45no7rE)9VRE6Qw96B3.Y5*ewKk9)HbS/By!}{42.z)LSRyj{L*.=enXt>)\\o3j%4Fh</hq88B\\6Jn4dK&7.TGxK5{57Sj?bjq46QTgXb,mn{{L5S-"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "facebook", "", 13, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "m1v1cX4w1GqGR"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 1, 7, "", "c"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "ab", "c", 20, 1, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "TsyhbLw16Sn1V1d2BZO1"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 4, 1, "0123456789", "El)f"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "a", "bc", 64, 2, "abcdefghijklmnopqrstuvwxyzABCDEFGHIKLMNOPQRSTVXYZ", "35%)..64}:3}\"027'83#`48~0}380?4&=,22776$`^]_330{2-+80\"41#7>4$$\\."},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 13, 2, "abcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "YCJCLRJMQMYMF"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "example.com", "john.doe@example.com", 128, 7, "lOI01|`'\"", "w~2j9MaTe^PM6UpvJxFCCNxNn75syuT46Mn>;jb;\\93N)JW6&83a{G4ggYBJr!24$>MV,?7m}Jk-Q~7254m]@R!s),$_e7u^82i424XxsxU~k9<6~nHFL.98Wv:(23/L"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 20, 7, "aeiou!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "t71l27bADv2Q9AfkNFIw"},
+	{4, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π-site", "ユーザー", 255, 1, "", "rSG8561]vXXi_5!CEG71)~#)Aeo{c8PKbdp7\"Of$'~cr/5z}g@a9xTCr=cfL0wT62!362z5152SRZ2SB=yGSr]Uv8gA94D0T|'}1+a\"9F7Nlc5o5`wr2r|aDyzQ5C]|ur7)E0aDdqT2n_W88('O~sa!x=U6vs8+czb9<O#SC]0+24>yhR#&x.nGSnI?Q3249Nzt4$62}J!ar)6@^_t~T8V8I78MU5J4Y*gb]0N]1~O75?6=FM!Tr)!MS>y0RuVU"},
+	// Version 5, added with the PasswordVectorsVersion 3, deriving the websites of a namespace from its child seed
+	{5, "11050255b2ff001d", "google", "a@a", 20, 1, "", "cJ568'[bEw)9/Tc3|UYw"},
+	{5, "11050255b2ff001d", "work/github.com", "a@a", 20, 1, "", "NF97U0m)^Dk5H{xw2&#e"},
+	{5, "11050255b2ff001d", "work/eng/github.com", "john", 16, 2, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "v3WyK84Eyv33VtNm"},
+	{5, "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", "π/サイト", "ユーザー", 32, 1, "lOI01|`'\"", "y]-8s.5bfz8kx5RL~>M5.U79~Z*mX5SK"},
+	// the part before the first / of a website is not a namespace if it contains : or ., as for a URL
+	{5, "11050255b2ff001d", "https://github.com", "a@a", 20, 1, "", "W0.`WM~0w3*ySxqxI85\""},
+	{5, "11050255b2ff001d", "github.com/login", "a@a", 20, 1, "", "g5tKR;YG3z\\L/y^43j$9"},
+}
+
+// passwordModeVectorType is a vector of the password derivation version 4 with the settings
+// added to identifications after version 4, added with the PasswordVectorsVersion 2
+type passwordModeVectorType struct {
+	seed                string // hexadecimal
+	website             string
+	user                string
+	passwordLength      uint16
+	round               uint16
+	unallowedCharacters string // as stored in the database
+	passwordRules       string
+	passwordMode        string
+	modeOptions         string
+	password            string
+}
+
+var passwordModeVectors = []passwordModeVectorType{
+	{"11050255b2ff001d", "google", "a@a", 300, 1, "", "", "", "", "DkauCLtP6bZ24o7?q_f%b55f^4VnA3z{{08GG41It8^M,<R)BX4&Q$ZJ4QIRCT7pE49:lJ7tW1u4k{+)em|30PI.u?6%\\5rbe6-RM3H|xg_yN0S@^S@\"|T(7J7b?fzA'Zvl|1*6<9iditx20s74a(1d3YZf_\"$HVr7F3&HP\\B/uMsI(IbCj+U(w]C1crT:zb7i\\OE[6_6R`490p8Fx9wj.j]F(l.46}[@ZL3056i042)W7<]LLF111\")R-L1=?n%.,s8\"2uAh521vM-po4uKm~F774Cg7OK)dWl)E^bZhbz5"},
+	{"11050255b2ff001d", "mybank.com", "john", 12, 1, "", "minlength: 8; maxlength: 16; required: [-!]; required: lower; allowed: lower, [-!]; max-consecutive: 2", "", "", "nkvhjysmes!a"},
+	{"11050255b2ff001d", "mybank.com", "john", 16, 2, "!", "minlength: 8; maxlength: 16; required: [-!]; required: lower; allowed: lower, [-!]; max-consecutive: 2", "", "", "xnozatpaqb-qxvvp"},
+	{"11050255b2ff001d", "wifi", "", 6, 1, "", "", "words", "{\"separator\":\"-\",\"capitalize\":false}", "alley-hungry-gorilla-boy-brief-matter"},
+	{"11050255b2ff001d", "wifi", "", 4, 2, "", "", "words", "{\"separator\":\" \",\"capitalize\":true}", "Must Width Indicate Mom"},
+	{"11050255b2ff001d", "carrier", "a@a", 6, 1, "", "", "pin", "{\"reject_weak\":true}", "957060"},
+	{"11050255b2ff001d", "google", "a@a", 4, 3, "", "", "pin", "{\"reject_weak\":false}", "0120"},
+	{"11050255b2ff001d", "google", "a@a", 4, 3, "", "", "pin", "{\"reject_weak\":true}", "3783"},
+	{"11050255b2ff001d", "phone", "a@a", 20, 1, "", "", "readable", "", "gE^Y7&956<e4&zV@bVyL"},
+	{"11050255b2ff001d", "phone", "a@a", 12, 1, "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", "", "readable", "", "xFs6yB37gES8"},
+	{"11050255b2ff001d", "radio", "a@a", 32, 1, "", "", "pronounceable", "", "rohJabmahPavDicMitMircefzulGevdo"},
+	{"11050255b2ff001d", "database", "root", 64, 1, "", "", "alphabet", "{\"alphabet\":\"0123456789abcdef\"}", "c4167890380dce507aea5a9c8884a08cadf9e6faad9d1ac34bc64af06ce4634f"},
+	{"11050255b2ff001d", "database", "root", 512, 1, "", "", "alphabet", "{\"alphabet\":\"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_\"}", "8EhmH4ZQTIQtcO1gnq-61KJsoooE6gY869_ZuGfa6dJNRacjkrMWkq_wGcO0Wj0_aAJlGwO8FhMGs07QIPo0gH6GS2rYj8rV7hg-uANtK9F20COANP6QdTCoLUO_FIKDWykxRRsP_8zPjefmAWJoldpZx3f5AQmgT3Q2bX18q20ZNuOT7BtLKlvjSNVWk7aoHj_gu0QIP2MiatFXurWJtlF80F4-29j7frkE52TxK182ILSpABubnfQzZOeDRsInWCDHRdpC9ZZWD4zWvifPJwfHziWgEdCQKGemy67ts91R_rq9BY1agqnLVFp02b456GqVE3YM6fIsY_A4xUbxAzw8vtghdSmZpPtU2vMR1hlQwesGW7qnQtaoxVANPKR69CyJW29XyqT89qmL-EKJa5LX44dEjkwDiA-gaZnouHPANbQCW4QPcxqEE055Rr_wiZxaQhyDTopylIHP3u6cA1xE20gCmHrCvsqOpoO3TECfa3a1U-cNhz5b1AxKmBGm"},
+	{"11050255b2ff001d", "totp", "a@a", 26, 1, "", "", "alphabet", "{\"alphabet\":\"ABCDEFGHIJKLMNOPQRSTUVWXYZ234567\"}", "MRHNIMW3QT3UUW7QTELTES6U2N"},
+	{"11050255b2ff001d", "π-site", "ユーザー", 10, 1, "", "", "alphabet", "{\"alphabet\":\"αβγδ\"}", "βδδδβγδγγδ"},
+}
@@ -0,0 +1,339 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/s2k"
+	"golang.org/x/crypto/sha3"
+)
+
+// PGPKeyDerivationVersion is the version of the derivation of the OpenPGP keys, recorded with each key
+const PGPKeyDerivationVersion = 1
+
+// pgpKeyDerivationSalt separates the OpenPGP keys from the passwords and any other secret derived from the seed
+const pgpKeyDerivationSalt = "derivatex pgp key derivation v1"
+
+// Identifiers of the OpenPGP format of RFC 4880, with the Ed25519 and Curve25519 keys of RFC 4880bis
+// which the golang.org/x/crypto/openpgp package does not support
+const (
+	pgpTagSignature    = 2
+	pgpTagSecretKey    = 5
+	pgpTagPublicKey    = 6
+	pgpTagSecretSubkey = 7
+	pgpTagUserID       = 13
+	pgpTagPublicSubkey = 14
+
+	pgpAlgorithmECDH  = 18
+	pgpAlgorithmEdDSA = 22
+	pgpHashSHA256     = 8
+	pgpHashSHA384     = 9
+	pgpHashSHA512     = 10
+	pgpCipherAES128   = 7
+	pgpCipherAES192   = 8
+	pgpCipherAES256   = 9
+
+	pgpSignaturePositiveCertification = 0x13
+	pgpSignatureSubkeyBinding         = 0x18
+
+	pgpSubpacketCreationTime         = 2
+	pgpSubpacketPreferredCiphers     = 11
+	pgpSubpacketIssuer               = 16
+	pgpSubpacketPreferredHashes      = 21
+	pgpSubpacketPreferredCompression = 22
+	pgpSubpacketKeyFlags             = 27
+	pgpSubpacketFeatures             = 30
+	pgpSubpacketIssuerFingerprint    = 33
+
+	pgpKeyFlagsCertifySign = 0x03
+	pgpKeyFlagsEncrypt     = 0x0c
+
+	pgpS2KUsageEncrypted = 254
+	pgpS2KIteratedSalted = 3
+	pgpS2KCount          = 0xff // 65011712 bytes hashed, the maximum of OpenPGP
+)
+
+var (
+	pgpOIDEd25519    = []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0xda, 0x47, 0x0f, 0x01}
+	pgpOIDCurve25519 = []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0x97, 0x55, 0x01, 0x05, 0x01}
+	// pgpECDHParameters are the KDF parameters of the encryption subkey: SHA-256 and AES-128 key wrapping
+	pgpECDHParameters = []byte{3, 1, pgpHashSHA256, pgpCipherAES128}
+)
+
+// PGPKeyType is an OpenPGP key derived from the seed, made of an Ed25519 primary key certifying the user ID
+// and signing, and of a Curve25519 subkey encrypting
+type PGPKeyType struct {
+	UserID              string
+	CreationTime        uint32
+	signingKey          ed25519.PrivateKey
+	encryptionKey       [32]byte // little endian and clamped as for X25519
+	encryptionPublicKey [32]byte
+}
+
+// DerivePGPKey derives the OpenPGP key of the label with HKDF-SHA3-256 from the seed. The creation time is part
+// of the fingerprint of the key, so it has to be the same every time the key is derived.
+func DerivePGPKey(seed *[]byte, label, userID string, creationTime int64, derivationVersion uint16) (key *PGPKeyType, err error) {
+	if derivationVersion != PGPKeyDerivationVersion {
+		return nil, errors.New("OpenPGP key derivation version " + strconv.FormatUint(uint64(derivationVersion), 10) + " is not supported by this program, please update it")
+	}
+	if userID == "" {
+		return nil, errors.New("the user ID of an OpenPGP key can't be empty")
+	}
+	if creationTime < 0 || creationTime > math.MaxUint32 {
+		return nil, errors.New("the creation time " + strconv.FormatInt(creationTime, 10) + " can't be represented in an OpenPGP key")
+	}
+	keySeeds := make([]byte, ed25519.SeedSize+32)
+	defer ClearByteSlice(&keySeeds)
+	io.ReadFull(hkdf.New(sha3.New256, *seed, []byte(pgpKeyDerivationSalt), lengthPrefixed(label)), keySeeds)
+	key = &PGPKeyType{
+		UserID:       userID,
+		CreationTime: uint32(creationTime),
+		signingKey:   ed25519.NewKeyFromSeed(keySeeds[:ed25519.SeedSize]),
+	}
+	copy(key.encryptionKey[:], keySeeds[ed25519.SeedSize:])
+	key.encryptionKey[0] &= 248
+	key.encryptionKey[31] &= 127
+	key.encryptionKey[31] |= 64
+	curve25519.ScalarBaseMult(&key.encryptionPublicKey, &key.encryptionKey)
+	return key, nil
+}
+
+// Clear overwrites the private keys in memory
+func (key *PGPKeyType) Clear() {
+	ClearByteSlice((*[]byte)(&key.signingKey))
+	for i := range key.encryptionKey {
+		key.encryptionKey[i] = 0
+	}
+}
+
+// pgpPacket returns the data preceded by the new format header of the packet tag
+func pgpPacket(tag byte, data []byte) []byte {
+	packet := []byte{0xc0 | tag}
+	switch length := len(data); {
+	case length < 192:
+		packet = append(packet, byte(length))
+	case length < 8384:
+		length -= 192
+		packet = append(packet, byte(length>>8)+192, byte(length))
+	default:
+		packet = append(packet, 0xff, byte(length>>24), byte(length>>16), byte(length>>8), byte(length))
+	}
+	return append(packet, data...)
+}
+
+// pgpMPI returns the big endian integer preceded by its length in bits, without its leading zero bytes
+func pgpMPI(integer []byte) []byte {
+	for len(integer) > 0 && integer[0] == 0 {
+		integer = integer[1:]
+	}
+	bits := 0
+	if len(integer) > 0 {
+		bits = 8*(len(integer)-1) + 1
+		for b := integer[0] >> 1; b > 0; b >>= 1 {
+			bits++
+		}
+	}
+	return append([]byte{byte(bits >> 8), byte(bits)}, integer...)
+}
+
+// pgpSubpacket returns the data of the signature subpacket preceded by its length and type
+func pgpSubpacket(subpacketType byte, data ...byte) []byte {
+	return append([]byte{byte(len(data) + 1), subpacketType}, data...)
+}
+
+func (key *PGPKeyType) primaryPublicBody() []byte {
+	body := []byte{4}
+	body = append(body, uint32Bytes(key.CreationTime)...)
+	body = append(body, pgpAlgorithmEdDSA, byte(len(pgpOIDEd25519)))
+	body = append(body, pgpOIDEd25519...)
+	return append(body, pgpMPI(append([]byte{0x40}, key.signingKey.Public().(ed25519.PublicKey)...))...)
+}
+
+func (key *PGPKeyType) subkeyPublicBody() []byte {
+	body := []byte{4}
+	body = append(body, uint32Bytes(key.CreationTime)...)
+	body = append(body, pgpAlgorithmECDH, byte(len(pgpOIDCurve25519)))
+	body = append(body, pgpOIDCurve25519...)
+	body = append(body, pgpMPI(append([]byte{0x40}, key.encryptionPublicKey[:]...))...)
+	return append(body, pgpECDHParameters...)
+}
+
+func uint32Bytes(n uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], n)
+	return b[:]
+}
+
+// pgpKeyHashPrefix returns the public key body as hashed by the signatures
+func pgpKeyHashPrefix(publicBody []byte) []byte {
+	return append([]byte{0x99, byte(len(publicBody) >> 8), byte(len(publicBody))}, publicBody...)
+}
+
+func (key *PGPKeyType) fingerprint() []byte {
+	digest := sha1.Sum(pgpKeyHashPrefix(key.primaryPublicBody()))
+	return digest[:]
+}
+
+// Fingerprint returns the V4 fingerprint of the primary key in hexadecimal
+func (key *PGPKeyType) Fingerprint() string {
+	return strings.ToUpper(hex.EncodeToString(key.fingerprint()))
+}
+
+// signature returns the body of a signature packet of the primary key over the hashed data and the subpackets
+func (key *PGPKeyType) signature(signatureType byte, hashedData []byte, subpackets []byte) []byte {
+	hashed := []byte{4, signatureType, pgpAlgorithmEdDSA, pgpHashSHA256, byte(len(subpackets) >> 8), byte(len(subpackets))}
+	hashed = append(hashed, subpackets...)
+	h := sha256.New()
+	h.Write(hashedData)
+	h.Write(hashed)
+	h.Write([]byte{4, 0xff})
+	h.Write(uint32Bytes(uint32(len(hashed))))
+	digest := h.Sum(nil)
+	signature := ed25519.Sign(key.signingKey, digest)
+
+	unhashed := pgpSubpacket(pgpSubpacketIssuer, key.fingerprint()[12:]...)
+	body := append(hashed, byte(len(unhashed)>>8), byte(len(unhashed)))
+	body = append(body, unhashed...)
+	body = append(body, digest[:2]...)
+	body = append(body, pgpMPI(signature[:32])...)
+	return append(body, pgpMPI(signature[32:])...)
+}
+
+// signatures returns the signature packets certifying the user ID and binding the subkey. They are created
+// at the creation time of the key and Ed25519 signatures are deterministic, so they are the same every time.
+func (key *PGPKeyType) signatures() (certification, binding []byte) {
+	creationTime := pgpSubpacket(pgpSubpacketCreationTime, uint32Bytes(key.CreationTime)...)
+	issuer := pgpSubpacket(pgpSubpacketIssuerFingerprint, append([]byte{4}, key.fingerprint()...)...)
+	primaryPrefix := pgpKeyHashPrefix(key.primaryPublicBody())
+
+	subpackets := append(append([]byte{}, creationTime...), issuer...)
+	subpackets = append(subpackets, pgpSubpacket(pgpSubpacketKeyFlags, pgpKeyFlagsCertifySign)...)
+	subpackets = append(subpackets, pgpSubpacket(pgpSubpacketPreferredCiphers, pgpCipherAES256, pgpCipherAES192, pgpCipherAES128)...)
+	subpackets = append(subpackets, pgpSubpacket(pgpSubpacketPreferredHashes, pgpHashSHA512, pgpHashSHA384, pgpHashSHA256)...)
+	subpackets = append(subpackets, pgpSubpacket(pgpSubpacketPreferredCompression, 2, 3, 1)...) // ZLIB, BZip2, ZIP
+	subpackets = append(subpackets, pgpSubpacket(pgpSubpacketFeatures, 1)...)                   // modification detection
+	userID := append([]byte{0xb4}, uint32Bytes(uint32(len(key.UserID)))...)
+	userID = append(userID, []byte(key.UserID)...)
+	certification = key.signature(pgpSignaturePositiveCertification, append(append([]byte{}, primaryPrefix...), userID...), subpackets)
+
+	subpackets = append(append([]byte{}, creationTime...), issuer...)
+	subpackets = append(subpackets, pgpSubpacket(pgpSubpacketKeyFlags, pgpKeyFlagsEncrypt)...)
+	binding = key.signature(pgpSignatureSubkeyBinding, append(primaryPrefix, pgpKeyHashPrefix(key.subkeyPublicBody())...), subpackets)
+	return pgpPacket(pgpTagSignature, certification), pgpPacket(pgpTagSignature, binding)
+}
+
+// pgpSecretKeyMaterial returns the secret integers of a key, in clear with their checksum if the passphrase is empty,
+// or else encrypted with AES-256 in CFB mode with a key derived from the passphrase with the iterated and salted SHA-256
+func pgpSecretKeyMaterial(secret []byte, passphrase *[]byte, ioReadFull ioReadFullFunc) (material []byte, err error) {
+	if passphrase == nil || len(*passphrase) == 0 {
+		var checksum uint16
+		for _, b := range secret {
+			checksum += uint16(b)
+		}
+		material = append([]byte{0}, secret...)
+		return append(material, byte(checksum>>8), byte(checksum)), nil
+	}
+	salt := make([]byte, 8)
+	iv := make([]byte, aes.BlockSize)
+	_, err = ioReadFull(rand.Reader, salt)
+	if err == nil {
+		_, err = ioReadFull(rand.Reader, iv)
+	}
+	if err != nil {
+		return nil, err
+	}
+	encryptionKey := make([]byte, 32)
+	defer ClearByteSlice(&encryptionKey)
+	s2k.Iterated(encryptionKey, sha256.New(), *passphrase, salt, (16+(pgpS2KCount&15))<<((pgpS2KCount>>4)+6))
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha1.Sum(secret)
+	plaintext := append(append([]byte{}, secret...), digest[:]...)
+	defer ClearByteSlice(&plaintext)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCFBEncrypter(block, iv).XORKeyStream(ciphertext, plaintext)
+
+	material = []byte{pgpS2KUsageEncrypted, pgpCipherAES256, pgpS2KIteratedSalted, pgpHashSHA256}
+	material = append(material, salt...)
+	material = append(material, pgpS2KCount)
+	material = append(material, iv...)
+	return append(material, ciphertext...), nil
+}
+
+func armorPGP(blockType string, packets []byte) (armored []byte, err error) {
+	var buffer bytes.Buffer
+	w, err := armor.Encode(&buffer, blockType, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(packets)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+// ArmoredPublicKey returns the transferable public key, identical every time the key is derived
+func (key *PGPKeyType) ArmoredPublicKey() (armored []byte, err error) {
+	certification, binding := key.signatures()
+	packets := pgpPacket(pgpTagPublicKey, key.primaryPublicBody())
+	packets = append(packets, pgpPacket(pgpTagUserID, []byte(key.UserID))...)
+	packets = append(packets, certification...)
+	packets = append(packets, pgpPacket(pgpTagPublicSubkey, key.subkeyPublicBody())...)
+	packets = append(packets, binding...)
+	return armorPGP("PGP PUBLIC KEY BLOCK", packets)
+}
+
+// ArmoredPrivateKey returns the transferable secret key, with its secret keys encrypted with the passphrase
+// as GnuPG does if the passphrase is not empty
+func (key *PGPKeyType) ArmoredPrivateKey(passphrase *[]byte, ioReadFull ioReadFullFunc) (armored []byte, err error) {
+	signingSecret := pgpMPI(key.signingKey.Seed())
+	defer ClearByteSlice(&signingSecret)
+	primaryMaterial, err := pgpSecretKeyMaterial(signingSecret, passphrase, ioReadFull)
+	if err != nil {
+		return nil, err
+	}
+	defer ClearByteSlice(&primaryMaterial)
+	var reversed [32]byte // the Curve25519 secret is stored big endian
+	for i := range reversed {
+		reversed[i] = key.encryptionKey[31-i]
+	}
+	encryptionSecret := pgpMPI(reversed[:])
+	reversed = [32]byte{}
+	defer ClearByteSlice(&encryptionSecret)
+	subkeyMaterial, err := pgpSecretKeyMaterial(encryptionSecret, passphrase, ioReadFull)
+	if err != nil {
+		return nil, err
+	}
+	defer ClearByteSlice(&subkeyMaterial)
+
+	certification, binding := key.signatures()
+	packets := pgpPacket(pgpTagSecretKey, append(key.primaryPublicBody(), primaryMaterial...))
+	defer ClearByteSlice(&packets)
+	packets = append(packets, pgpPacket(pgpTagUserID, []byte(key.UserID))...)
+	packets = append(packets, certification...)
+	packets = append(packets, pgpPacket(pgpTagSecretSubkey, append(key.subkeyPublicBody(), subkeyMaterial...))...)
+	packets = append(packets, binding...)
+	return armorPGP("PGP PRIVATE KEY BLOCK", packets)
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/s2k"
+)
+
+const pgpTestUserID = "Alice <alice@example.com>"
+
+func Test_pgpMPI(t *testing.T) {
+	cases := []struct {
+		integer  []byte
+		expected string
+	}{
+		{[]byte{}, "0000"},
+		{[]byte{0, 0}, "0000"},
+		{[]byte{1}, "000101"},
+		{[]byte{0, 0x7f, 0xff}, "000f7fff"},
+		{[]byte{0x40, 0}, "000f4000"},
+		{[]byte{0xff, 1}, "0010ff01"},
+	}
+	for _, c := range cases {
+		if mpi := hex.EncodeToString(pgpMPI(c.integer)); mpi != c.expected {
+			t.Errorf("pgpMPI(%x) == %s want %s", c.integer, mpi, c.expected)
+		}
+	}
+}
+
+func Test_pgpPacket(t *testing.T) {
+	cases := []struct {
+		length   int
+		expected string
+	}{
+		{0, "cd00"},
+		{191, "cdbf"},
+		{192, "cdc000"},
+		{8383, "cddfff"},
+		{8384, "cdff000020c0"},
+	}
+	for _, c := range cases {
+		packet := pgpPacket(pgpTagUserID, make([]byte, c.length))
+		header := hex.EncodeToString(packet[:len(packet)-c.length])
+		if header != c.expected {
+			t.Errorf("pgpPacket() of %d bytes has the header %s want %s", c.length, header, c.expected)
+		}
+	}
+}
+
+func TestDerivePGPKey(t *testing.T) {
+	seed := []byte{1, 2, 3, 4}
+	key, err := DerivePGPKey(&seed, "work", pgpTestUserID, 1600000000, PGPKeyDerivationVersion)
+	if err != nil {
+		t.Fatalf("DerivePGPKey() - %s", err)
+	}
+	const expected = "EB57F8815F736C298CFE1D41F00F556EBB4618BF"
+	if key.Fingerprint() != expected {
+		t.Errorf("DerivePGPKey() has the fingerprint %s want %s", key.Fingerprint(), expected)
+	}
+	otherUserID, _ := DerivePGPKey(&seed, "work", "Bob <bob@example.com>", 1600000000, PGPKeyDerivationVersion)
+	if otherUserID.Fingerprint() != expected {
+		t.Errorf("DerivePGPKey() has a fingerprint depending on the user ID")
+	}
+	otherLabel, _ := DerivePGPKey(&seed, "home", pgpTestUserID, 1600000000, PGPKeyDerivationVersion)
+	otherTime, _ := DerivePGPKey(&seed, "work", pgpTestUserID, 1600000001, PGPKeyDerivationVersion)
+	if otherLabel.Fingerprint() == expected || otherTime.Fingerprint() == expected {
+		t.Errorf("DerivePGPKey() has the same fingerprint for a different label or creation time")
+	}
+	sshKey, _ := DeriveSSHKey(&seed, "work", SSHKeyDerivationVersion)
+	if bytes.Equal(sshKey, key.signingKey) || bytes.Equal(key.signingKey.Seed(), key.encryptionKey[:]) {
+		t.Errorf("DerivePGPKey() derives the same key as the SSH key or as its subkey")
+	}
+	if _, err = DerivePGPKey(&seed, "work", pgpTestUserID, 1600000000, 2); err == nil {
+		t.Errorf("DerivePGPKey() with an unknown version should fail")
+	}
+	if _, err = DerivePGPKey(&seed, "work", "", 1600000000, PGPKeyDerivationVersion); err == nil {
+		t.Errorf("DerivePGPKey() without user ID should fail")
+	}
+	if _, err = DerivePGPKey(&seed, "work", pgpTestUserID, 1<<32, PGPKeyDerivationVersion); err == nil {
+		t.Errorf("DerivePGPKey() with a creation time after 2106 should fail")
+	}
+}
+
+// decodePGPArmor returns the packets of the armored data, failing the test if the block type is not the expected one
+func decodePGPArmor(t *testing.T, armored []byte, blockType string) []byte {
+	block, err := armor.Decode(bytes.NewReader(armored))
+	if err != nil || block.Type != blockType {
+		t.Fatalf("armor.Decode() - %v, block %v want %s", err, block, blockType)
+	}
+	packets, err := ioutil.ReadAll(block.Body)
+	if err != nil {
+		t.Fatalf("armor.Decode() - %s", err)
+	}
+	return packets
+}
+
+func TestArmoredPGPKey(t *testing.T) {
+	seed := []byte{1, 2, 3, 4}
+	key, _ := DerivePGPKey(&seed, "work", pgpTestUserID, 1600000000, PGPKeyDerivationVersion)
+	publicKey, err := key.ArmoredPublicKey()
+	if err != nil {
+		t.Fatalf("ArmoredPublicKey() - %s", err)
+	}
+	again, _ := DerivePGPKey(&seed, "work", pgpTestUserID, 1600000000, PGPKeyDerivationVersion)
+	if publicKeyAgain, _ := again.ArmoredPublicKey(); !bytes.Equal(publicKey, publicKeyAgain) {
+		t.Errorf("ArmoredPublicKey() is different when the key is derived again")
+	}
+	packets := decodePGPArmor(t, publicKey, "PGP PUBLIC KEY BLOCK")
+	primary := pgpPacket(pgpTagPublicKey, key.primaryPublicBody())
+	if !bytes.HasPrefix(packets, primary) || !bytes.Contains(packets, pgpPacket(pgpTagUserID, []byte(pgpTestUserID))) ||
+		!bytes.Contains(packets, pgpPacket(pgpTagPublicSubkey, key.subkeyPublicBody())) {
+		t.Errorf("ArmoredPublicKey() misses the primary key, the user ID or the subkey")
+	}
+
+	privateKey, err := key.ArmoredPrivateKey(nil, io.ReadFull)
+	if err != nil {
+		t.Fatalf("ArmoredPrivateKey() - %s", err)
+	}
+	packets = decodePGPArmor(t, privateKey, "PGP PRIVATE KEY BLOCK")
+	secret := pgpMPI(key.signingKey.Seed())
+	var checksum uint16
+	for _, b := range secret {
+		checksum += uint16(b)
+	}
+	primary = pgpPacket(pgpTagSecretKey, append(append(append(key.primaryPublicBody(), 0), secret...), byte(checksum>>8), byte(checksum)))
+	if !bytes.HasPrefix(packets, primary) {
+		t.Errorf("ArmoredPrivateKey() does not start with the unencrypted primary key")
+	}
+
+	passphrase := []byte("correct horse")
+	privateKey, err = key.ArmoredPrivateKey(&passphrase, io.ReadFull)
+	if err != nil {
+		t.Fatalf("ArmoredPrivateKey() with a passphrase - %s", err)
+	}
+	packets = decodePGPArmor(t, privateKey, "PGP PRIVATE KEY BLOCK")
+	publicBody := key.primaryPublicBody()
+	material := packets[2+len(publicBody) : 2+packets[1]]
+	if material[0] != pgpS2KUsageEncrypted || material[1] != pgpCipherAES256 || material[2] != pgpS2KIteratedSalted || material[3] != pgpHashSHA256 {
+		t.Fatalf("ArmoredPrivateKey() with a passphrase is encrypted with %x", material[:4])
+	}
+	encryptionKey := make([]byte, 32)
+	s2k.Iterated(encryptionKey, sha256.New(), passphrase, material[4:12], 65011712)
+	block, _ := aes.NewCipher(encryptionKey)
+	plaintext := make([]byte, len(material)-29)
+	cipher.NewCFBDecrypter(block, material[13:29]).XORKeyStream(plaintext, material[29:])
+	digest := sha1.Sum(secret)
+	if !bytes.Equal(plaintext, append(secret, digest[:]...)) {
+		t.Errorf("ArmoredPrivateKey() with a passphrase can't be decrypted")
+	}
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/techsek/derivatex/constants"
+)
+
+// MakeSeedSalt returns a random salt to derive the seed instead of the birthdate
+func MakeSeedSalt(ioReadFull ioReadFullFunc) (salt *[]byte, err error) {
+	salt = new([]byte)
+	*salt = make([]byte, constants.SeedSaltSize)
+	_, err = ioReadFull(rand.Reader, *salt)
+	if err != nil {
+		ClearByteSlice(salt)
+		return nil, err
+	}
+	return salt, nil
+}
+
+// recoveryArgonParams are the Argon2ID parameters of the seed for each version of the recovery code, frozen so
+// that a recovery code recreates the same seed after the default parameters change. A new version is added each
+// time DefaultSeedArgonParams change, the recovery codes written before the versions being of the version 1.
+var recoveryArgonParams = map[byte]ArgonParamsType{
+	1: {400, 512 * 1024, 4},
+}
+
+// EncodeRecoveryCode encodes the version of the Argon2ID parameters of the seed, the seed salt and
+// their checksum in base32, in groups of characters easy to write down, i.e. ABCD-EFGH-...
+func EncodeRecoveryCode(salt *[]byte, params ArgonParamsType) (code string, err error) {
+	version := byte(0)
+	for v, p := range recoveryArgonParams {
+		if p == params {
+			version = v
+		}
+	}
+	if version == 0 {
+		return "", errors.New("The Argon2ID parameters " + params.String() + " of the seed have no recovery code version")
+	}
+	data := new([]byte)
+	*data = append(*data, version)
+	*data = append(*data, *salt...)
+	Checksumize(data)
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(*data)
+	ClearByteSlice(data)
+	var groups []string
+	for len(encoded) > constants.RecoveryCodeGroupSize {
+		groups = append(groups, encoded[:constants.RecoveryCodeGroupSize])
+		encoded = encoded[constants.RecoveryCodeGroupSize:]
+	}
+	groups = append(groups, encoded)
+	return strings.Join(groups, "-"), nil
+}
+
+// DecodeRecoveryCode returns the seed salt and the Argon2ID parameters of the seed from the recovery code,
+// ignoring the case, dashes and spaces, and verifying its checksum. Recovery codes without version,
+// which only contain the salt, use the parameters of the version 1.
+func DecodeRecoveryCode(code string) (salt *[]byte, params ArgonParamsType, err error) {
+	code = strings.ToUpper(code)
+	code = strings.Replace(code, "-", "", -1)
+	code = strings.Replace(code, " ", "", -1)
+	data := new([]byte)
+	*data, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(code)
+	if err != nil {
+		return nil, params, errors.New("The recovery code contains invalid characters")
+	}
+	if len(*data) != constants.SeedSaltSize+4 && len(*data) != 1+constants.SeedSaltSize+4 {
+		ClearByteSlice(data)
+		return nil, params, errors.New("The recovery code does not have the right length")
+	}
+	err = Dechecksumize(data)
+	if err != nil {
+		ClearByteSlice(data)
+		return nil, params, errors.New("The recovery code is invalid (" + err.Error() + ")")
+	}
+	version := byte(1)
+	if len(*data) > constants.SeedSaltSize {
+		version = (*data)[0]
+	}
+	params, ok := recoveryArgonParams[version]
+	if !ok {
+		ClearByteSlice(data)
+		return nil, params, errors.New("The recovery code version " + strconv.Itoa(int(version)) + " is not supported by this program, please update it")
+	}
+	salt = new([]byte)
+	*salt = append([]byte{}, (*data)[len(*data)-constants.SeedSaltSize:]...)
+	ClearByteSlice(data)
+	return salt, params, nil
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestMakeSeedSalt(t *testing.T) {
+	salt, err := MakeSeedSalt(func(reader io.Reader, buf []byte) (n int, err error) { return 0, errors.New("unexpected EOF") })
+	equal, m := errorsEqual(err, errors.New("unexpected EOF"))
+	if !equal || salt != nil {
+		t.Errorf("MakeSeedSalt() - %s", m)
+	}
+	salt, err = MakeSeedSalt(io.ReadFull)
+	if err != nil {
+		t.Fatalf("MakeSeedSalt() - %s", err)
+	}
+	if len(*salt) != 16 {
+		t.Errorf("MakeSeedSalt() returned %d bytes instead of 16", len(*salt))
+	}
+}
+
+func TestEncodeRecoveryCode(t *testing.T) {
+	cases := []struct {
+		salt []byte
+		code string
+	}{
+		{
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			"AEAA-CAQD-AQCQ-MBYI-BEFA-WDAN-BYHX-N62A-LY",
+		},
+		{
+			[]byte{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+			"AH77-7777-7777-7777-7777-7777-7775-IYDM-7E",
+		},
+	}
+	for _, c := range cases {
+		out, err := EncodeRecoveryCode(&c.salt, recoveryArgonParams[1])
+		if err != nil || out != c.code {
+			t.Errorf("EncodeRecoveryCode(%v) == %s, %v want %s", c.salt, out, err, c.code)
+		}
+	}
+	salt := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	if _, err := EncodeRecoveryCode(&salt, ArgonParamsType{1, 1024, 1}); err == nil {
+		t.Errorf("EncodeRecoveryCode() with Argon2ID parameters without version should fail")
+	}
+	// a new version of the recovery code is needed each time the default parameters change
+	if _, err := EncodeRecoveryCode(&salt, DefaultSeedArgonParams); err != nil {
+		t.Errorf("EncodeRecoveryCode() with the default Argon2ID parameters - %s", err)
+	}
+}
+
+func TestDecodeRecoveryCode(t *testing.T) {
+	cases := []struct {
+		code string
+		salt []byte
+		err  error
+	}{
+		{
+			"AEAA-CAQD-AQCQ-MBYI-BEFA-WDAN-BYHX-N62A-LY",
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			nil,
+		},
+		{ // written before the versions
+			"AAAQ-EAYE-AUDA-OCAJ-BIFQ-YDIO-B44U-MLJK",
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			nil,
+		},
+		{
+			"A4AA-CAQD-AQCQ-MBYI-BEFA-WDAN-BYH5-Z6ED-24",
+			nil,
+			errors.New("The recovery code version 7 is not supported by this program, please update it"),
+		},
+		{
+			"aaaq eaye auda ocaj bifq ydio b44u mljk",
+			[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			nil,
+		},
+		{
+			"AAAQ-EAYE-AUDA-OCAJ-BIFQ-YDIO-B44U-MLJL",
+			nil,
+			errors.New("The recovery code is invalid (Checksum verification failed)"),
+		},
+		{
+			"AAAQ-EAYE-AUDA-OCAJ-BIFQ-YDIO-B44U",
+			nil,
+			errors.New("The recovery code does not have the right length"),
+		},
+		{
+			"AAAQ-EAYE-AUDA-OCAJ-BIFQ-YDIO-B44U-MLJ1",
+			nil,
+			errors.New("The recovery code contains invalid characters"),
+		},
+	}
+	for _, c := range cases {
+		out, params, err := DecodeRecoveryCode(c.code)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("DecodeRecoveryCode(%s) - %s", c.code, m)
+		}
+		if err == nil && (!reflect.DeepEqual(*out, c.salt) || params != recoveryArgonParams[1]) {
+			t.Errorf("DecodeRecoveryCode(%s) == %v, %s want %v, %s", c.code, *out, params, c.salt, recoveryArgonParams[1])
+		}
+	}
+}
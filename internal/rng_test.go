@@ -38,3 +38,68 @@ func Test_randInt64(t *testing.T) {
 		}
 	}
 }
+
+func Test_hkdfStream(t *testing.T) {
+	s := newHKDFStream([]byte{1, 2, 3}, []byte("test"))
+	expected := map[int]uint32{
+		0:    2974108492,
+		1:    2547199169,
+		2:    2367152706,
+		2038: 1032201619,
+		2039: 2614361736,
+		2040: 913695105, // first integer of the second HKDF block
+	}
+	for i := 0; i <= 2040; i++ {
+		v := s.randUint32()
+		if e, ok := expected[i]; ok && v != e {
+			t.Errorf("randUint32() number %d == %d want %d", i, v, e)
+		}
+	}
+}
+
+func Test_uniformInt(t *testing.T) {
+	s := newHKDFStream([]byte{1, 2, 3}, []byte("test"))
+	const n, draws = 3, 30000
+	var counts [n]int
+	for i := 0; i < draws; i++ {
+		v := s.uniformInt(n)
+		if v < 0 || v >= n {
+			t.Fatalf("uniformInt(%d) == %d out of range", n, v)
+		}
+		counts[v]++
+	}
+	for v, count := range counts {
+		if count < draws/n*95/100 || count > draws/n*105/100 {
+			t.Errorf("uniformInt(%d) returned %d %d times out of %d", n, v, count, draws)
+		}
+	}
+	if s.uniformInt(1) != 0 {
+		t.Errorf("uniformInt(1) != 0")
+	}
+}
+
+func Test_legacyRandSource(t *testing.T) {
+	// Outputs of rand.NewSource(seed).Int63() captured from math/rand, at calls 1, 2 and 1000
+	cases := []struct {
+		seed                    int64
+		first, second, thousand int64
+	}{
+		{0, 8717895732742165505, 2259404117704393152, 8063246301045201982},
+		{1, 5577006791947779410, 8674665223082153551, 4156721158850876882},
+		{-1, 3644962268338389676, 550171362161912239, 5061947166444481062},
+		{89482311, 8717895732742165505, 2259404117704393152, 8063246301045201982},
+		{2147483647, 8717895732742165505, 2259404117704393152, 8063246301045201982},
+		{4611686018427387904, 5577006791947779410, 8674665223082153551, 4156721158850876882},
+		{-6143396225497698318, 4121743896984498761, 7402407096413544041, 7106453483187264522},
+	}
+	for _, c := range cases {
+		r := newLegacySource(c.seed)
+		var outputs []int64
+		for i := 0; i < 1000; i++ {
+			outputs = append(outputs, r.randInt63())
+		}
+		if outputs[0] != c.first || outputs[1] != c.second || outputs[999] != c.thousand {
+			t.Errorf("newLegacySource(%d) produced %d, %d and %d want %d, %d and %d", c.seed, outputs[0], outputs[1], outputs[999], c.first, c.second, c.thousand)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/techsek/derivatex/constants"
+)
+
+// ArgonParamsType contains the Argon2ID parameters used to derive a key
+type ArgonParamsType struct {
+	TimeCost    uint32
+	MemoryKB    uint32
+	Parallelism uint8
+}
+
+func (params ArgonParamsType) String() string {
+	return "time=" + strconv.FormatUint(uint64(params.TimeCost), 10) +
+		" memory=" + strconv.FormatUint(uint64(params.MemoryKB), 10) + "KiB" +
+		" parallelism=" + strconv.FormatUint(uint64(params.Parallelism), 10)
+}
+
+func parseArgonParams(s string) (params ArgonParamsType, err error) {
+	_, err = fmt.Sscanf(s, "time=%d memory=%dKiB parallelism=%d", &params.TimeCost, &params.MemoryKB, &params.Parallelism)
+	if err != nil {
+		return params, errors.New("Argon2ID parameters '" + s + "' are malformed (" + err.Error() + ")")
+	}
+	if params.TimeCost == 0 || params.MemoryKB == 0 || params.Parallelism == 0 {
+		return params, errors.New("Argon2ID parameters '" + s + "' must all be strictly positive")
+	}
+	return params, nil
+}
+
+// Parameters of the current release, only used for newly written seed files
+var (
+	DefaultSeedArgonParams       = ArgonParamsType{constants.ArgonTimeCost, constants.ArgonMemoryMB * 1024, constants.ArgonParallelism}
+	DefaultPassphraseArgonParams = ArgonParamsType{constants.PassphraseArgonTimeCost, constants.PassphraseArgonMemoryMB * 1024, constants.PassphraseArgonParallelism}
+)
+
+// Parameters frozen for seed files written before the format was versioned
+var (
+	legacySeedArgonParams       = ArgonParamsType{400, 512 * 1024, 4}
+	legacyPassphraseArgonParams = ArgonParamsType{10, 100 * 1024, 4}
+)
+
+const (
+	seedProtectionNone              = "none"
+	seedProtectionPassphrase        = "passphrase"
+	seedProtectionKeyfile           = "keyfile"
+	seedProtectionPassphraseKeyfile = "passphrase+keyfile"
+	seedCipherNone                  = "none"
+	seedCipherAESCFB                = "aes-256-cfb" // legacy, unauthenticated
+	seedCipherAESGCM                = "aes-256-gcm"
+	seedSaltBirthdate               = "birthdate"
+	seedSaltRandom                  = "random"  // written down by the user as a recovery code
+	seedSaltUnknown                 = "unknown" // seed restored from a backup
+	seedSaltParent                  = "parent"  // child seed derived from a parent seed
+)
+
+// SeedFileType is the content of the seed file, the seed being encrypted
+// according to Cipher if Protection is not none
+type SeedFileType struct {
+	Version         uint16
+	DefaultUser     string
+	Protection      string
+	Cipher          string
+	SeedSalt        string
+	SeedArgon       ArgonParamsType
+	PassphraseArgon ArgonParamsType
+	PassphraseSalt  []byte
+	Namespace       string // namespace of the websites of a child seed, empty for other seeds
+	Seed            *[]byte
+}
+
+// NewSeedFile returns an unprotected seed file using the parameters of the current release
+func NewSeedFile(defaultUser string, seed *[]byte) *SeedFileType {
+	return &SeedFileType{
+		Version:         constants.SeedFileVersion,
+		DefaultUser:     defaultUser,
+		Protection:      seedProtectionNone,
+		Cipher:          seedCipherNone,
+		SeedSalt:        seedSaltBirthdate,
+		SeedArgon:       DefaultSeedArgonParams,
+		PassphraseArgon: DefaultPassphraseArgonParams,
+		PassphraseSalt:  []byte{},
+		Seed:            seed,
+	}
+}
+
+// NeedsUpgrade returns true if the seed file uses an older format or a legacy cipher
+func (seedFile *SeedFileType) NeedsUpgrade() bool {
+	return seedFile.Version < constants.SeedFileVersion || seedFile.Cipher == seedCipherAESCFB
+}
+
+// UseRandomSalt records that the seed was derived with a random salt instead of the birthdate
+func (seedFile *SeedFileType) UseRandomSalt() {
+	seedFile.SeedSalt = seedSaltRandom
+}
+
+// UseUnknownSalt records that the seed was restored from a backup, without knowing how it was derived
+func (seedFile *SeedFileType) UseUnknownSalt() {
+	seedFile.SeedSalt = seedSaltUnknown
+}
+
+// IsProtected returns true if the seed has to be decrypted before use
+func (seedFile *SeedFileType) IsProtected() bool {
+	return seedFile.Protection != seedProtectionNone
+}
+
+// RequiresPassphrase returns true if the passphrase is one of the factors needed to decrypt the seed
+func (seedFile *SeedFileType) RequiresPassphrase() bool {
+	return seedFile.Protection == seedProtectionPassphrase || seedFile.Protection == seedProtectionPassphraseKeyfile
+}
+
+// RequiresKeyfile returns true if the keyfile is one of the factors needed to decrypt the seed
+func (seedFile *SeedFileType) RequiresKeyfile() bool {
+	return seedFile.Protection == seedProtectionKeyfile || seedFile.Protection == seedProtectionPassphraseKeyfile
+}
+
+const (
+	seedVersionPrefix = "Derivatex seed file version: "
+	seedSecretPrefix  = "Secret Seed: "
+)
+
+// header returns all the non secret lines of the seed file, in a canonical order
+func (seedFile *SeedFileType) header() []byte {
+	lines := []string{
+		seedVersionPrefix + strconv.FormatUint(uint64(seedFile.Version), 10),
+		"Default user: " + seedFile.DefaultUser,
+		"Protection: " + seedFile.Protection,
+		"Cipher: " + seedFile.Cipher,
+		"Seed salt: " + seedFile.SeedSalt,
+		"Seed Argon2ID: " + seedFile.SeedArgon.String(),
+		"Passphrase Argon2ID: " + seedFile.PassphraseArgon.String(),
+		"Passphrase salt: " + base64.StdEncoding.EncodeToString(seedFile.PassphraseSalt),
+	}
+	if seedFile.Namespace != "" { // only written for child seeds, so that the header of other seed files is unchanged
+		lines = append(lines, "Namespace: "+seedFile.Namespace)
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func (seedFile *SeedFileType) serialize() (content *[]byte) {
+	content = new([]byte)
+	*content = append(*content, seedFile.header()...)
+	*content = append(*content, []byte(seedSecretPrefix)...)
+	*content = append(*content, []byte(base64.StdEncoding.EncodeToString(*seedFile.Seed))...)
+	return content
+}
+
+// parseSeed reads the content of the seed file, which is cleared in the process
+func parseSeed(content *[]byte) (seedFile *SeedFileType, err error) {
+	defer ClearByteSlice(content)
+	if bytes.HasPrefix(*content, []byte("Default user: ")) {
+		return parseLegacySeed(content)
+	}
+	if !bytes.HasPrefix(*content, []byte(seedVersionPrefix)) {
+		return nil, errors.New("'" + seedVersionPrefix + "' must be the start of " + constants.SeedFilename)
+	}
+	i := bytes.Index(*content, []byte(seedSecretPrefix))
+	if i < 0 {
+		return nil, errors.New("'" + seedSecretPrefix + "' not found in " + constants.SeedFilename)
+	}
+	header := string((*content)[:i])
+	clearAndTrim(content, i+len(seedSecretPrefix))
+	seedFile = new(SeedFileType)
+	var seedArgonFound, passphraseArgonFound bool
+	for _, line := range strings.Split(strings.TrimSuffix(header, "\n"), "\n") {
+		j := strings.Index(line, ": ")
+		if j < 0 {
+			return nil, errors.New("Line '" + line + "' of " + constants.SeedFilename + " is malformed")
+		}
+		key, value := line[:j], line[j+2:]
+		switch key {
+		case "Derivatex seed file version":
+			version, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return nil, errors.New("Version '" + value + "' of " + constants.SeedFilename + " is malformed")
+			}
+			if version > constants.SeedFileVersion {
+				return nil, errors.New(constants.SeedFilename + " version " + value + " is not supported by this program, please update it")
+			}
+			seedFile.Version = uint16(version)
+		case "Default user":
+			seedFile.DefaultUser = value
+		case "Protection":
+			seedFile.Protection = value
+		case "Cipher":
+			seedFile.Cipher = value
+		case "Seed salt":
+			seedFile.SeedSalt = value
+		case "Seed Argon2ID":
+			seedFile.SeedArgon, err = parseArgonParams(value)
+			seedArgonFound = true
+		case "Passphrase Argon2ID":
+			seedFile.PassphraseArgon, err = parseArgonParams(value)
+			passphraseArgonFound = true
+		case "Passphrase salt":
+			seedFile.PassphraseSalt, err = base64.StdEncoding.DecodeString(value)
+		case "Namespace":
+			seedFile.Namespace, err = ParseNamespace(value)
+		default:
+			return nil, errors.New("Unknown field '" + key + "' in " + constants.SeedFilename)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if seedFile.Protection == "" || seedFile.Cipher == "" {
+		return nil, errors.New("Protection and cipher must be specified in " + constants.SeedFilename)
+	}
+	switch seedFile.Protection {
+	case seedProtectionNone, seedProtectionPassphrase, seedProtectionKeyfile, seedProtectionPassphraseKeyfile:
+	default:
+		return nil, errors.New("Protection '" + seedFile.Protection + "' of " + constants.SeedFilename + " is not supported by this program, please update it")
+	}
+	if !seedArgonFound {
+		return nil, errors.New("Seed Argon2ID parameters must be specified in " + constants.SeedFilename)
+	}
+	if !passphraseArgonFound && seedFile.IsProtected() {
+		return nil, errors.New("Passphrase Argon2ID parameters must be specified in " + constants.SeedFilename + " protected by " + seedFile.Protection)
+	}
+	seedFile.Seed = new([]byte)
+	*seedFile.Seed, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(*content)))
+	if err != nil {
+		return nil, err
+	}
+	return seedFile, nil
+}
+
+// parseLegacySeed reads the unversioned seed file format, which always used
+// the Argon2ID parameters frozen in legacySeedArgonParams and legacyPassphraseArgonParams
+func parseLegacySeed(content *[]byte) (seedFile *SeedFileType, err error) {
+	seedFile = &SeedFileType{
+		Version:         1,
+		Cipher:          seedCipherNone,
+		SeedSalt:        seedSaltBirthdate,
+		SeedArgon:       legacySeedArgonParams,
+		PassphraseArgon: legacyPassphraseArgonParams,
+		PassphraseSalt:  []byte{},
+	}
+	// Reading the file step by step instead of with bytes.Split() to avoid using more memory than necessary for security purposes
+	var i int
+	clearAndTrim(content, len([]byte("Default user: ")))
+	i = bytes.Index(*content, []byte("\n"))
+	if i < 0 {
+		return nil, errors.New("New line not found after 'Default user: ' in " + constants.SeedFilename)
+	}
+	seedFile.DefaultUser = string((*content)[:i])
+	clearAndTrim(content, i+len([]byte("\n")))
+	i = bytes.Index(*content, []byte("Protection: "))
+	if i < 0 {
+		return nil, errors.New("'Protection: ' not found in " + constants.SeedFilename)
+	}
+	clearAndTrim(content, i+len([]byte("Protection: ")))
+	i = bytes.Index(*content, []byte("\n"))
+	if i < 0 {
+		return nil, errors.New("New line not found after 'Protection: ' in " + constants.SeedFilename)
+	}
+	seedFile.Protection = string((*content)[:i])
+	if seedFile.Protection == seedProtectionPassphrase {
+		seedFile.Cipher = seedCipherAESCFB
+	}
+	clearAndTrim(content, i+len([]byte("\n")))
+	i = bytes.Index(*content, []byte(seedSecretPrefix))
+	if i < 0 {
+		return nil, errors.New("'" + seedSecretPrefix + "' not found in " + constants.SeedFilename)
+	}
+	clearAndTrim(content, i+len([]byte(seedSecretPrefix)))
+	seedFile.Seed = new([]byte)
+	*seedFile.Seed, err = base64.StdEncoding.DecodeString(string(*content))
+	if err != nil {
+		return nil, err
+	}
+	return seedFile, nil
+}
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_parseArgonParams(t *testing.T) {
+	cases := []struct {
+		s      string
+		params ArgonParamsType
+		err    error
+	}{
+		{
+			"time=400 memory=524288KiB parallelism=4",
+			ArgonParamsType{400, 524288, 4},
+			nil,
+		},
+		{
+			"time=0 memory=524288KiB parallelism=4",
+			ArgonParamsType{},
+			errors.New("Argon2ID parameters 'time=0 memory=524288KiB parallelism=4' must all be strictly positive"),
+		},
+		{
+			"time=400",
+			ArgonParamsType{},
+			errors.New("Argon2ID parameters 'time=400' are malformed (unexpected EOF)"),
+		},
+	}
+	for _, c := range cases {
+		out, err := parseArgonParams(c.s)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("parseArgonParams(%s) - %s", c.s, m)
+		}
+		if err == nil && out != c.params {
+			t.Errorf("parseArgonParams(%s) == %v want %v", c.s, out, c.params)
+		}
+		if err == nil && out.String() != c.s {
+			t.Errorf("%v.String() == %s want %s", out, out.String(), c.s)
+		}
+	}
+}
+
+func Test_parseSeed(t *testing.T) {
+	cases := []struct {
+		content  string
+		seedFile *SeedFileType
+		err      error
+	}{
+		{
+			"Derivatex seed file version: 2\nDefault user: a@a\nProtection: passphrase\nCipher: aes-256-cfb\nSeed salt: birthdate\nSeed Argon2ID: time=5 memory=1024KiB parallelism=2\nPassphrase Argon2ID: time=3 memory=2048KiB parallelism=1\nPassphrase salt: AQID\nSecret Seed: EQUCVQ==",
+			&SeedFileType{
+				Version:         2,
+				DefaultUser:     "a@a",
+				Protection:      "passphrase",
+				Cipher:          "aes-256-cfb",
+				SeedSalt:        "birthdate",
+				SeedArgon:       ArgonParamsType{5, 1024, 2},
+				PassphraseArgon: ArgonParamsType{3, 2048, 1},
+				PassphraseSalt:  []byte{1, 2, 3},
+				Seed:            &[]byte{17, 5, 2, 85},
+			},
+			nil,
+		},
+		{
+			"Default user: a@a\nProtection: passphrase\nSecret Seed: EQUCVQ==",
+			&SeedFileType{
+				Version:         1,
+				DefaultUser:     "a@a",
+				Protection:      "passphrase",
+				Cipher:          "aes-256-cfb",
+				SeedSalt:        "birthdate",
+				SeedArgon:       ArgonParamsType{400, 524288, 4},
+				PassphraseArgon: ArgonParamsType{10, 102400, 4},
+				PassphraseSalt:  []byte{},
+				Seed:            &[]byte{17, 5, 2, 85},
+			},
+			nil,
+		},
+		{
+			"Default user: \nProtection: none\nSecret Seed: EQUCVQ==",
+			&SeedFileType{
+				Version:         1,
+				DefaultUser:     "",
+				Protection:      "none",
+				Cipher:          "none",
+				SeedSalt:        "birthdate",
+				SeedArgon:       ArgonParamsType{400, 524288, 4},
+				PassphraseArgon: ArgonParamsType{10, 102400, 4},
+				PassphraseSalt:  []byte{},
+				Seed:            &[]byte{17, 5, 2, 85},
+			},
+			nil,
+		},
+		{
+			"Derivatex seed file version: 3\nSecret Seed: EQUCVQ==",
+			nil,
+			errors.New("seed.txt version 3 is not supported by this program, please update it"),
+		},
+		{
+			"Derivatex seed file version: 2\nColor: blue\nSecret Seed: EQUCVQ==",
+			nil,
+			errors.New("Unknown field 'Color' in seed.txt"),
+		},
+		{
+			"Derivatex seed file version: 2\nProtection: fingerprint\nCipher: aes-256-gcm\nSecret Seed: EQUCVQ==",
+			nil,
+			errors.New("Protection 'fingerprint' of seed.txt is not supported by this program, please update it"),
+		},
+		{
+			"Derivatex seed file version: 2\nDefault user: a@a\n",
+			nil,
+			errors.New("'Secret Seed: ' not found in seed.txt"),
+		},
+		{ // truncated before the Argon2ID parameters
+			"Derivatex seed file version: 2\nDefault user: a@a\nProtection: none\nCipher: none\nSeed salt: birthdate\nSecret Seed: EQUCVQ==",
+			nil,
+			errors.New("Seed Argon2ID parameters must be specified in seed.txt"),
+		},
+		{
+			"Derivatex seed file version: 2\nDefault user: a@a\nProtection: passphrase\nCipher: aes-256-gcm\nSeed salt: birthdate\nSeed Argon2ID: time=5 memory=1024KiB parallelism=2\nPassphrase salt: AQID\nSecret Seed: EQUCVQ==",
+			nil,
+			errors.New("Passphrase Argon2ID parameters must be specified in seed.txt protected by passphrase"),
+		},
+		{
+			"Derivatex seed file version: 2\nDefault user: a@a\nProtection: keyfile\nCipher: aes-256-gcm\nSeed salt: birthdate\nSeed Argon2ID: time=5 memory=1024KiB parallelism=2\nPassphrase Argon2ID: time=3 memory=0KiB parallelism=1\nPassphrase salt: AQID\nSecret Seed: EQUCVQ==",
+			nil,
+			errors.New("Argon2ID parameters 'time=3 memory=0KiB parallelism=1' must all be strictly positive"),
+		},
+		{
+			"Secret Seed: EQUCVQ==",
+			nil,
+			errors.New("'Derivatex seed file version: ' must be the start of seed.txt"),
+		},
+	}
+	for _, c := range cases {
+		content := []byte(c.content)
+		out, err := parseSeed(&content)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("parseSeed(%s) - %s", c.content, m)
+		}
+		if err == nil && !reflect.DeepEqual(out, c.seedFile) {
+			t.Errorf("parseSeed(%s) == %v want %v", c.content, out, c.seedFile)
+		}
+	}
+}
+
+func Test_serializeParseSeed(t *testing.T) {
+	seedFile := NewSeedFile("a@a", &[]byte{17, 5, 2, 85, 178, 255, 0, 29})
+	out, err := parseSeed(seedFile.serialize())
+	if err != nil {
+		t.Fatalf("parseSeed(serialize()) - %s", err)
+	}
+	if !reflect.DeepEqual(out, seedFile) {
+		t.Errorf("parseSeed(serialize()) == %v want %v", out, seedFile)
+	}
+}
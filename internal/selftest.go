@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// CheckPasswordVectors derives the password of every known answer vector and returns the
+// number of vectors checked for each password derivation version, or an error for the first mismatch
+func CheckPasswordVectors() (counts map[uint16]int, err error) {
+	counts = make(map[uint16]int)
+	for i, vector := range passwordVectors {
+		seed, err := hex.DecodeString(vector.seed)
+		if err != nil {
+			return nil, errors.New("Vector " + strconv.Itoa(i+1) + " has a malformed seed (" + err.Error() + ")")
+		}
+		websiteSeed, err := deriveWebsiteSeed(&seed, vector.website, vector.passwordDerivationVersion)
+		if err != nil {
+			return nil, errors.New("Vector " + strconv.Itoa(i+1) + " has no website seed (" + err.Error() + ")")
+		}
+		passwordDigest := MakePasswordDigest(websiteSeed, vector.website, vector.user, vector.passwordDerivationVersion)
+		ClearByteSlice(websiteSeed)
+		// the unallowed characters of the vectors are migrated as the identifications of the database
+		constraints := NewCharacterConstraints(false, false, false, false, vector.unallowedCharacters)
+		password, err := SatisfyPassword(passwordDigest, uint16(vector.passwordLength), vector.round, constraints, vector.passwordDerivationVersion)
+		ClearByteArray32(passwordDigest)
+		if err != nil || password != vector.password {
+			return nil, errors.New("Vector " + strconv.Itoa(i+1) + " of version " + strconv.FormatUint(uint64(vector.passwordDerivationVersion), 10) +
+				" for website '" + vector.website + "' and user '" + vector.user + "' produced '" + password + "' instead of '" + vector.password + "'")
+		}
+		counts[vector.passwordDerivationVersion]++
+	}
+	for i, vector := range passwordModeVectors {
+		seed, err := hex.DecodeString(vector.seed)
+		if err != nil {
+			return nil, errors.New("Mode vector " + strconv.Itoa(i+1) + " has a malformed seed (" + err.Error() + ")")
+		}
+		identification := IdentificationType{
+			Website:                   vector.website,
+			User:                      vector.user,
+			PasswordLength:            vector.passwordLength,
+			Round:                     vector.round,
+			Constraints:               NewCharacterConstraints(false, false, false, false, vector.unallowedCharacters),
+			PasswordDerivationVersion: 4,
+			PasswordRules:             vector.passwordRules,
+			PasswordMode:              vector.passwordMode,
+			ModeOptions:               vector.modeOptions,
+		}
+		passwordDigest := MakePasswordDigest(&seed, vector.website, vector.user, identification.PasswordDerivationVersion)
+		password, err := GeneratePassword(passwordDigest, &identification)
+		ClearByteArray32(passwordDigest)
+		if err != nil || password != vector.password {
+			return nil, errors.New("Mode vector " + strconv.Itoa(i+1) + " of mode '" + vector.passwordMode +
+				"' for website '" + vector.website + "' and user '" + vector.user + "' produced '" + password + "' instead of '" + vector.password + "'")
+		}
+		counts[identification.PasswordDerivationVersion]++
+	}
+	return counts, nil
+}
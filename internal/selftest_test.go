@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/techsek/derivatex/constants"
+)
+
+func TestCheckPasswordVectors(t *testing.T) {
+	counts, err := CheckPasswordVectors()
+	if err != nil {
+		t.Fatalf("CheckPasswordVectors() - %s", err)
+	}
+	for version := uint16(1); version <= constants.PasswordDerivationVersion; version++ {
+		if counts[version] == 0 {
+			t.Errorf("CheckPasswordVectors() has no vector for version %d", version)
+		}
+	}
+}
+
+func TestCheckPasswordVectorsMismatch(t *testing.T) {
+	vectors := passwordVectors
+	defer func() { passwordVectors = vectors }()
+	passwordVectors = []passwordVectorType{vectors[0]}
+	passwordVectors[0].password += "x"
+	_, err := CheckPasswordVectors()
+	if err == nil {
+		t.Errorf("CheckPasswordVectors() did not detect a modified vector")
+	}
+	passwordVectors = vectors
+	modeVectors := passwordModeVectors
+	defer func() { passwordModeVectors = modeVectors }()
+	passwordModeVectors = []passwordModeVectorType{modeVectors[len(modeVectors)-1]}
+	passwordModeVectors[0].password = "βδδδβγδγγα"
+	_, err = CheckPasswordVectors()
+	if err == nil {
+		t.Errorf("CheckPasswordVectors() did not detect a modified mode vector")
+	}
+}
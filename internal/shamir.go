@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// Shamir secret sharing over GF(256), using the AES polynomial x^8 + x^4 + x^3 + x + 1
+
+var gf256Exp, gf256Log = makeGF256Tables()
+
+func makeGF256Tables() (exp [510]byte, log [256]byte) {
+	var x byte = 1
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		exp[i+255] = x
+		log[x] = byte(i)
+		// multiply x by the generator 3
+		high := x & 0x80
+		x2 := x << 1
+		if high != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+	return exp, log
+}
+
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
+
+// ShareType is one share of a secret split with SplitSecret
+type ShareType struct {
+	ID        uint16 // random identifier common to all the shares of a split
+	Threshold uint8
+	Index     uint8 // x coordinate, from 1 to the number of shares
+	Data      *[]byte
+}
+
+const shareHeaderSize = 4
+
+// SplitSecret splits the secret in n shares, any threshold of which can recreate the secret
+func SplitSecret(secret *[]byte, n, threshold uint8, ioReadFull ioReadFullFunc) (shares []ShareType, err error) {
+	if threshold < 2 {
+		return nil, errors.New("The threshold must be at least 2")
+	}
+	if n < threshold {
+		return nil, errors.New("The number of shares must be at least the threshold " + strconv.Itoa(int(threshold)))
+	}
+	random := new([]byte)
+	defer ClearByteSlice(random)
+	*random = make([]byte, 2+len(*secret)*int(threshold-1))
+	_, err = ioReadFull(rand.Reader, *random)
+	if err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(*random)
+	coefficients := (*random)[2:]
+	for i := uint8(1); i <= n; i++ {
+		share := ShareType{ID: id, Threshold: threshold, Index: i, Data: new([]byte)}
+		*share.Data = make([]byte, len(*secret))
+		for j := range *secret {
+			// Horner's method on the polynomial of degree threshold-1 whose constant is the secret byte
+			var y byte
+			for k := int(threshold) - 2; k >= 0; k-- {
+				y = gf256Mul(y, i) ^ coefficients[j*int(threshold-1)+k]
+			}
+			(*share.Data)[j] = gf256Mul(y, i) ^ (*secret)[j]
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+// ValidateShares verifies the shares belong to the same split and are all different
+func ValidateShares(shares []ShareType) error {
+	if len(shares) == 0 {
+		return errors.New("No share to combine")
+	}
+	first := shares[0]
+	for i, share := range shares {
+		if share.ID != first.ID || share.Threshold != first.Threshold || len(*share.Data) != len(*first.Data) {
+			return errors.New("Share " + strconv.Itoa(int(share.Index)) + " does not belong to the same split as share " + strconv.Itoa(int(first.Index)))
+		}
+		if share.Index == 0 {
+			return errors.New("Share index 0 is not valid")
+		}
+		for _, other := range shares[:i] {
+			if share.Index == other.Index {
+				return errors.New("Share " + strconv.Itoa(int(share.Index)) + " was given more than once")
+			}
+		}
+	}
+	return nil
+}
+
+// CombineShares recreates the secret from at least a threshold of shares of the same split
+func CombineShares(shares []ShareType) (secret *[]byte, err error) {
+	err = ValidateShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	first := shares[0]
+	if len(shares) < int(first.Threshold) {
+		return nil, errors.New(strconv.Itoa(int(first.Threshold)) + " shares are needed but only " + strconv.Itoa(len(shares)) + " were given")
+	}
+	shares = shares[:first.Threshold]
+	secret = new([]byte)
+	*secret = make([]byte, len(*first.Data))
+	for i, share := range shares {
+		// Lagrange basis polynomial of the share evaluated at x = 0
+		var basis byte = 1
+		for j, other := range shares {
+			if i != j {
+				basis = gf256Mul(basis, gf256Div(other.Index, other.Index^share.Index))
+			}
+		}
+		for k := range *secret {
+			(*secret)[k] ^= gf256Mul(basis, (*share.Data)[k])
+		}
+	}
+	return secret, nil
+}
+
+// Serialize returns the share with its header, to be encoded with its checksum i.e. with MakeMnemonic
+func (share *ShareType) Serialize() (data *[]byte) {
+	data = new([]byte)
+	*data = make([]byte, shareHeaderSize, shareHeaderSize+len(*share.Data))
+	binary.BigEndian.PutUint16(*data, share.ID)
+	(*data)[2] = share.Threshold
+	(*data)[3] = share.Index
+	*data = append(*data, *share.Data...)
+	return data
+}
+
+// ParseShare reads a share serialized with Serialize
+func ParseShare(data *[]byte) (share ShareType, err error) {
+	if len(*data) <= shareHeaderSize {
+		return share, errors.New("Share is too short")
+	}
+	share.ID = binary.BigEndian.Uint16(*data)
+	share.Threshold = (*data)[2]
+	share.Index = (*data)[3]
+	if share.Threshold < 2 || share.Index == 0 {
+		return share, errors.New("Share has an invalid threshold or index")
+	}
+	share.Data = new([]byte)
+	*share.Data = append(*share.Data, (*data)[shareHeaderSize:]...)
+	return share, nil
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func Test_gf256Mul(t *testing.T) {
+	cases := []struct {
+		a, b, product byte
+	}{
+		{0, 7, 0},
+		{1, 7, 7},
+		{2, 0x80, 0x1b},
+		{0x57, 0x83, 0xc1}, // FIPS 197 example
+		{0x57, 0x13, 0xfe}, // FIPS 197 example
+	}
+	for _, c := range cases {
+		out := gf256Mul(c.a, c.b)
+		if out != c.product {
+			t.Errorf("gf256Mul(%d, %d) == %d want %d", c.a, c.b, out, c.product)
+		}
+		if c.b != 0 && gf256Div(out, c.b) != c.a {
+			t.Errorf("gf256Div(%d, %d) == %d want %d", out, c.b, gf256Div(out, c.b), c.a)
+		}
+	}
+}
+
+func TestSplitCombineSecret(t *testing.T) {
+	secret := []byte{17, 5, 2, 85, 178, 255, 0, 29}
+	shares, err := SplitSecret(&secret, 5, 3, io.ReadFull)
+	if err != nil {
+		t.Fatalf("SplitSecret() - %s", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("SplitSecret() returned %d shares instead of 5", len(shares))
+	}
+	subsets := [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}}
+	for _, subset := range subsets {
+		var selected []ShareType
+		for _, i := range subset {
+			data := shares[i].Serialize()
+			share, err := ParseShare(data)
+			if err != nil {
+				t.Fatalf("ParseShare() - %s", err)
+			}
+			selected = append(selected, share)
+		}
+		out, err := CombineShares(selected)
+		if err != nil {
+			t.Errorf("CombineShares(%v) - %s", subset, err)
+			continue
+		}
+		if !reflect.DeepEqual(*out, secret) {
+			t.Errorf("CombineShares(%v) == %v want %v", subset, *out, secret)
+		}
+	}
+	out, err := CombineShares(shares[:2])
+	if err == nil && reflect.DeepEqual(*out, secret) {
+		t.Errorf("CombineShares() recreated the secret with less shares than the threshold")
+	}
+}
+
+func TestSplitSecret(t *testing.T) {
+	secret := []byte{17, 5}
+	zeroReadFull := func(reader io.Reader, buf []byte) (n int, err error) {
+		for i := range buf {
+			buf[i] = byte(i)
+		}
+		return len(buf), nil
+	}
+	shares, err := SplitSecret(&secret, 3, 2, zeroReadFull)
+	if err != nil {
+		t.Fatalf("SplitSecret() - %s", err)
+	}
+	expected := []ShareType{
+		{1, 2, 1, &[]byte{19, 6}},
+		{1, 2, 2, &[]byte{21, 3}},
+		{1, 2, 3, &[]byte{23, 0}},
+	}
+	if !reflect.DeepEqual(shares, expected) {
+		t.Errorf("SplitSecret(%v, 3, 2) == %v want %v", secret, shares, expected)
+	}
+	_, err = SplitSecret(&secret, 2, 3, zeroReadFull)
+	equal, m := errorsEqual(err, errors.New("The number of shares must be at least the threshold 3"))
+	if !equal {
+		t.Errorf("SplitSecret(%v, 2, 3) - %s", secret, m)
+	}
+	_, err = SplitSecret(&secret, 2, 1, zeroReadFull)
+	equal, m = errorsEqual(err, errors.New("The threshold must be at least 2"))
+	if !equal {
+		t.Errorf("SplitSecret(%v, 2, 1) - %s", secret, m)
+	}
+}
+
+func TestCombineShares(t *testing.T) {
+	cases := []struct {
+		shares []ShareType
+		secret []byte
+		err    error
+	}{
+		{
+			[]ShareType{{1, 2, 3, &[]byte{23, 0}}, {1, 2, 1, &[]byte{19, 6}}},
+			[]byte{17, 5},
+			nil,
+		},
+		{
+			[]ShareType{{1, 2, 3, &[]byte{23, 0}}},
+			nil,
+			errors.New("2 shares are needed but only 1 were given"),
+		},
+		{
+			[]ShareType{{1, 2, 3, &[]byte{23, 0}}, {2, 2, 1, &[]byte{19, 6}}},
+			nil,
+			errors.New("Share 1 does not belong to the same split as share 3"),
+		},
+		{
+			[]ShareType{{1, 2, 3, &[]byte{23, 0}}, {1, 2, 3, &[]byte{23, 0}}},
+			nil,
+			errors.New("Share 3 was given more than once"),
+		},
+		{
+			nil,
+			nil,
+			errors.New("No share to combine"),
+		},
+	}
+	for _, c := range cases {
+		out, err := CombineShares(c.shares)
+		equal, m := errorsEqual(err, c.err)
+		if !equal {
+			t.Errorf("CombineShares(%v) - %s", c.shares, m)
+		}
+		if err == nil && !reflect.DeepEqual(*out, c.secret) {
+			t.Errorf("CombineShares(%v) == %v want %v", c.shares, *out, c.secret)
+		}
+	}
+}
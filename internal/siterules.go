@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Site rules map website domains to their password rules, from the bundled rules of this program
+// overridden by the rules imported in the database of the profile.
+
+// SiteRulesType contains the password rules known for a domain
+type SiteRulesType struct {
+	Domain        string
+	PasswordRules string // canonical passwordrules
+	Source        string // SiteRulesBundled or SiteRulesImported
+}
+
+const (
+	// SiteRulesBundled is the source of the rules shipped with the program
+	SiteRulesBundled = "bundled"
+	// SiteRulesImported is the source of the rules imported with ImportSiteRules
+	SiteRulesImported = "imported"
+)
+
+func createSiteRulesTableIfNeeded() (err error) {
+	_, err = database.Exec("CREATE TABLE IF NOT EXISTS site_rules (domain TEXT PRIMARY KEY, password_rules TEXT NOT NULL)")
+	return err
+}
+
+// NormalizeDomain returns the lowercase host name of a website given as a domain or URL, without 'www.'
+func NormalizeDomain(website string) string {
+	domain := strings.ToLower(strings.TrimSpace(website))
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if i := strings.LastIndexByte(domain, '@'); i >= 0 {
+		domain = domain[i+1:]
+	}
+	if i := strings.IndexByte(domain, ':'); i >= 0 {
+		domain = domain[:i]
+	}
+	domain = strings.TrimSuffix(domain, ".")
+	return strings.TrimPrefix(domain, "www.")
+}
+
+// parentDomains returns the domain followed by its parent domains, i.e. login.bank.com and bank.com
+func parentDomains(domain string) (domains []string) {
+	for strings.Contains(domain, ".") {
+		domains = append(domains, domain)
+		domain = domain[strings.IndexByte(domain, '.')+1:]
+	}
+	return domains
+}
+
+// FindSiteRules returns the password rules known for the website or one of its parent domains,
+// the imported rules taking precedence over the bundled rules
+func FindSiteRules(website string) (siteRules SiteRulesType, found bool, err error) {
+	for _, domain := range parentDomains(NormalizeDomain(website)) {
+		var passwordRules string
+		err = database.QueryRow("SELECT password_rules FROM site_rules WHERE domain = ?", domain).Scan(&passwordRules)
+		if err == nil {
+			return SiteRulesType{domain, passwordRules, SiteRulesImported}, true, nil
+		}
+		if err != sql.ErrNoRows {
+			return siteRules, false, err
+		}
+		if passwordRules, ok := bundledSiteRules[domain]; ok {
+			rules, err := ParsePasswordRules(passwordRules)
+			if err != nil {
+				return siteRules, false, errors.New("Bundled password rules of " + domain + " are invalid: " + err.Error())
+			}
+			return SiteRulesType{domain, rules.String(), SiteRulesBundled}, true, nil
+		}
+	}
+	return siteRules, false, nil
+}
+
+// ParseSiteRulesFile parses site rules in the JSON format of the password rules quirks of
+// https://github.com/apple/password-manager-resources, i.e. {"bank.com": {"password-rules": "minlength: 8;"}},
+// returning them sorted by domain with canonical password rules
+func ParseSiteRulesFile(content []byte) (sitesRules []SiteRulesType, err error) {
+	var entries map[string]struct {
+		PasswordRules string `json:"password-rules"`
+	}
+	err = json.Unmarshal(content, &entries)
+	if err != nil {
+		return nil, errors.New("The site rules file is not valid JSON: " + err.Error())
+	}
+	for website, entry := range entries {
+		domain := NormalizeDomain(website)
+		if !strings.Contains(domain, ".") {
+			return nil, errors.New("'" + website + "' is not a domain")
+		}
+		rules, err := ParsePasswordRules(entry.PasswordRules)
+		if err != nil {
+			return nil, errors.New("Password rules of " + website + " are invalid: " + err.Error())
+		}
+		sitesRules = append(sitesRules, SiteRulesType{domain, rules.String(), SiteRulesImported})
+	}
+	sort.Slice(sitesRules, func(i, j int) bool { return sitesRules[i].Domain < sitesRules[j].Domain })
+	return sitesRules, nil
+}
+
+// ImportSiteRules stores the site rules in the database, replacing the rules imported previously for the same domains
+func ImportSiteRules(sitesRules []SiteRulesType) (err error) {
+	transaction, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	for _, siteRules := range sitesRules {
+		_, err = transaction.Exec("INSERT OR REPLACE INTO site_rules (domain, password_rules) VALUES (?, ?)", siteRules.Domain, siteRules.PasswordRules)
+		if err != nil {
+			transaction.Rollback()
+			return err
+		}
+	}
+	return transaction.Commit()
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeDomain(t *testing.T) {
+	cases := map[string]string{
+		"bank.com":                              "bank.com",
+		"WWW.Bank.com":                          "bank.com",
+		"https://www.bank.com/login?next=/home": "bank.com",
+		"http://user@login.bank.com:8080/":      "login.bank.com",
+		"google":                                "google",
+	}
+	for website, expected := range cases {
+		if out := NormalizeDomain(website); out != expected {
+			t.Errorf("NormalizeDomain(%q) == %q want %q", website, out, expected)
+		}
+	}
+}
+
+func Test_parentDomains(t *testing.T) {
+	out := parentDomains("a.login.bank.com")
+	expected := []string{"a.login.bank.com", "login.bank.com", "bank.com"}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("parentDomains() == %v want %v", out, expected)
+	}
+	if out := parentDomains("google"); len(out) != 0 {
+		t.Errorf("parentDomains(\"google\") == %v want none", out)
+	}
+}
+
+func Test_bundledSiteRules(t *testing.T) {
+	for domain, passwordRules := range bundledSiteRules {
+		if NormalizeDomain(domain) != domain {
+			t.Errorf("Bundled domain %q is not normalized", domain)
+		}
+		_, err := ParsePasswordRules(passwordRules)
+		if err != nil {
+			t.Errorf("Bundled password rules of %s - %s", domain, err)
+		}
+	}
+}
+
+func TestParseSiteRulesFile(t *testing.T) {
+	sitesRules, err := ParseSiteRulesFile([]byte(`{
+		"www.example.com": {"password-rules": "required: digit; minlength: 6;"},
+		"bank.com": {"password-rules": "maxlength: 12;"}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseSiteRulesFile() - %s", err)
+	}
+	expected := []SiteRulesType{
+		{"bank.com", "maxlength: 12; allowed: ascii-printable", SiteRulesImported},
+		{"example.com", "minlength: 6; required: digit; allowed: digit", SiteRulesImported},
+	}
+	if !reflect.DeepEqual(sitesRules, expected) {
+		t.Errorf("ParseSiteRulesFile() == %v want %v", sitesRules, expected)
+	}
+	for _, content := range []string{`[]`, `{"bank": {"password-rules": "minlength: 8;"}}`, `{"bank.com": {"password-rules": "length: 8;"}}`} {
+		_, err = ParseSiteRulesFile([]byte(content))
+		if err == nil {
+			t.Errorf("ParseSiteRulesFile(%s) should fail", content)
+		}
+	}
+}
+
+func TestFindImportSiteRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "derivatex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { vaultDirectory, vaultProfile = "", "" }()
+	_, err = SetVault(dir, "", false)
+	if err != nil {
+		t.Fatalf("SetVault() - %s", err)
+	}
+	err = InitiateDatabaseIfNeeded()
+	if err != nil {
+		t.Fatalf("InitiateDatabaseIfNeeded() - %s", err)
+	}
+	defer database.Close()
+
+	siteRules, found, err := FindSiteRules("https://secure.chase.com/login")
+	if err != nil || !found || siteRules.Domain != "chase.com" || siteRules.Source != SiteRulesBundled {
+		t.Errorf("FindSiteRules() of a bundled domain == %v, %t, %v", siteRules, found, err)
+	}
+	_, found, err = FindSiteRules("example.com")
+	if err != nil || found {
+		t.Errorf("FindSiteRules() of an unknown domain == %t, %v", found, err)
+	}
+
+	imported := []SiteRulesType{
+		{"chase.com", "maxlength: 12; allowed: ascii-printable", SiteRulesImported},
+		{"example.com", "minlength: 6; allowed: ascii-printable", SiteRulesImported},
+	}
+	err = ImportSiteRules(imported)
+	if err != nil {
+		t.Fatalf("ImportSiteRules() - %s", err)
+	}
+	for _, expected := range imported {
+		siteRules, found, err = FindSiteRules("www." + expected.Domain)
+		if err != nil || !found || siteRules != expected {
+			t.Errorf("FindSiteRules() of an imported domain == %v, %t, %v want %v", siteRules, found, err, expected)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package internal
+
+// bundledSiteRules are the known password rules of websites, by domain, in the passwordrules syntax.
+// They are based on the password rules quirks of https://github.com/apple/password-manager-resources
+// (MIT License), more recent rules can be imported with 'derivatex rules import'.
+var bundledSiteRules = map[string]string{
+	"allianz.com.br":       "minlength: 4; maxlength: 4;",
+	"americanexpress.com":  "minlength: 8; maxlength: 20; max-consecutive: 4; required: lower, upper; required: digit; allowed: [%&_?#=];",
+	"apple.com":            "minlength: 8; maxlength: 63; required: lower; required: upper; required: digit; allowed: ascii-printable;",
+	"bankofamerica.com":    "minlength: 8; maxlength: 20; max-consecutive: 3; required: lower; required: upper; required: digit; allowed: [-@#*()+={}/?~;,._];",
+	"battle.net":           "minlength: 8; maxlength: 16; required: lower, upper; allowed: digit, special;",
+	"chase.com":            "minlength: 8; maxlength: 32; max-consecutive: 2; required: lower, upper; required: digit; required: [!#$%+/=@~];",
+	"comcast.net":          "minlength: 8; maxlength: 32; required: lower, upper; required: digit; allowed: [-_.];",
+	"costco.com":           "minlength: 8; maxlength: 20; required: lower, upper; allowed: digit, [-!#$%&'()*+/:;=?@[^_`{|}~]];",
+	"dell.com":             "minlength: 8; maxlength: 20; required: lower; required: upper; required: digit; required: [!#$%&*+?@^];",
+	"discover.com":         "minlength: 6; maxlength: 32; max-consecutive: 3; required: lower, upper; required: digit; allowed: [@$_.];",
+	"ebay.com":             "minlength: 6; maxlength: 64; required: lower, upper; required: digit, [!@#$%^&*];",
+	"fidelity.com":         "minlength: 6; maxlength: 20; required: lower; allowed: upper, digit, [!$%'()+,./:;=?@^_|~];",
+	"hilton.com":           "minlength: 8; maxlength: 32; required: lower; required: upper; required: digit;",
+	"hsbc.com":             "minlength: 8; maxlength: 30; required: lower; required: upper; required: digit;",
+	"ing.com.au":           "minlength: 4; maxlength: 4; allowed: digit;",
+	"kaiserpermanente.org": "minlength: 8; maxlength: 20; max-consecutive: 2; required: lower; required: upper; required: digit; required: [-_.@];",
+	"lowes.com":            "minlength: 8; maxlength: 128; max-consecutive: 3; required: lower, upper; required: digit;",
+	"microsoft.com":        "minlength: 8; maxlength: 256; required: lower, upper; required: digit; required: special;",
+	"paypal.com":           "minlength: 8; maxlength: 20; max-consecutive: 3; required: lower, upper; required: digit, [!@#$%^&*()];",
+	"southwest.com":        "minlength: 8; maxlength: 16; required: upper; required: digit; allowed: lower, [!@#$%^*(),.;:/\\];",
+	"target.com":           "minlength: 8; maxlength: 20; required: lower, upper; required: digit, [-!\"#$%&'()*+,./:;=?@[\\^_`{|}~];",
+	"ups.com":              "minlength: 8; required: lower, upper; required: digit; required: [!@#$%^&*];",
+	"usaa.com":             "minlength: 8; maxlength: 12; required: lower; required: upper; required: digit; allowed: [-!@#$%^&*()_+=];",
+	"wellsfargo.com":       "minlength: 8; maxlength: 32; required: lower; required: upper; required: digit;",
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHKeyDerivationVersion is the version of the derivation of the SSH keys, recorded with each key
+const SSHKeyDerivationVersion = 1
+
+// sshKeyDerivationSalt separates the SSH keys from the passwords and any other secret derived from the seed
+const sshKeyDerivationSalt = "derivatex ssh key derivation v1"
+
+// Settings of the encryption of the OpenSSH private keys with a passphrase, as ssh-keygen
+const (
+	sshKeyCipher     = "aes256-ctr"
+	sshKeyKDF        = "bcrypt"
+	sshKeyKDFRounds  = 16
+	sshKeyKDFSaltLen = 16
+)
+
+// DeriveSSHKey derives the Ed25519 key of the label with HKDF-SHA3-256 from the seed
+func DeriveSSHKey(seed *[]byte, label string, derivationVersion uint16) (privateKey ed25519.PrivateKey, err error) {
+	if derivationVersion != SSHKeyDerivationVersion {
+		return nil, errors.New("SSH key derivation version " + strconv.FormatUint(uint64(derivationVersion), 10) + " is not supported by this program, please update it")
+	}
+	keySeed := make([]byte, ed25519.SeedSize)
+	io.ReadFull(hkdf.New(sha3.New256, *seed, []byte(sshKeyDerivationSalt), lengthPrefixed(label)), keySeed)
+	privateKey = ed25519.NewKeyFromSeed(keySeed)
+	ClearByteSlice(&keySeed)
+	return privateKey, nil
+}
+
+// sshString returns the data preceded by its length, as strings of the SSH wire format
+func sshString(data []byte) []byte {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	return append(length[:], data...)
+}
+
+// MarshalSSHPublicKey returns the public key in the authorized_keys format, followed by the comment
+func MarshalSSHPublicKey(publicKey ed25519.PublicKey, comment string) (authorizedKey []byte, fingerprint string, err error) {
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	authorizedKey = ssh.MarshalAuthorizedKey(sshPublicKey)
+	if comment != "" {
+		authorizedKey = append(authorizedKey[:len(authorizedKey)-1], []byte(" "+comment+"\n")...)
+	}
+	return authorizedKey, ssh.FingerprintSHA256(sshPublicKey), nil
+}
+
+// MarshalSSHPrivateKey returns the private key in the OpenSSH format, encrypted with the passphrase
+// with bcrypt_pbkdf and AES-256-CTR as ssh-keygen does if the passphrase is not empty
+func MarshalSSHPrivateKey(privateKey ed25519.PrivateKey, comment string, passphrase *[]byte, ioReadFull ioReadFullFunc) (pemBytes []byte, err error) {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	wirePublicKey := append(sshString([]byte(ssh.KeyAlgoED25519)), sshString(publicKey)...)
+
+	var checkInt [4]byte // to verify the decryption
+	_, err = ioReadFull(rand.Reader, checkInt[:])
+	if err != nil {
+		return nil, err
+	}
+	private := append(checkInt[:], checkInt[:]...)
+	private = append(private, sshString([]byte(ssh.KeyAlgoED25519))...)
+	private = append(private, sshString(publicKey)...)
+	private = append(private, sshString(privateKey)...)
+	private = append(private, sshString([]byte(comment))...)
+	defer ClearByteSlice(&private)
+
+	cipherName, kdfName, kdfOptions, blockSize := "none", "none", []byte{}, 8
+	encrypted := passphrase != nil && len(*passphrase) > 0
+	var salt []byte
+	if encrypted {
+		cipherName, kdfName, blockSize = sshKeyCipher, sshKeyKDF, aes.BlockSize
+		salt = make([]byte, sshKeyKDFSaltLen)
+		_, err = ioReadFull(rand.Reader, salt)
+		if err != nil {
+			return nil, err
+		}
+		var rounds [4]byte
+		binary.BigEndian.PutUint32(rounds[:], sshKeyKDFRounds)
+		kdfOptions = append(sshString(salt), rounds[:]...)
+	}
+	for i := byte(1); len(private)%blockSize != 0; i++ {
+		private = append(private, i)
+	}
+	if encrypted {
+		key, err := bcryptPBKDF(*passphrase, salt, sshKeyKDFRounds, 32+aes.BlockSize)
+		if err != nil {
+			return nil, err
+		}
+		defer ClearByteSlice(&key)
+		block, err := aes.NewCipher(key[:32])
+		if err != nil {
+			return nil, err
+		}
+		cipher.NewCTR(block, key[32:]).XORKeyStream(private, private)
+	}
+
+	data := []byte("openssh-key-v1\x00")
+	data = append(data, sshString([]byte(cipherName))...)
+	data = append(data, sshString([]byte(kdfName))...)
+	data = append(data, sshString(kdfOptions)...)
+	data = append(data, 0, 0, 0, 1) // number of keys
+	data = append(data, sshString(wirePublicKey)...)
+	data = append(data, sshString(private)...)
+	return pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: data}), nil
+}
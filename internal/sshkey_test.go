@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+func Test_bcryptPBKDF(t *testing.T) {
+	key, err := bcryptPBKDF([]byte("password"), []byte("salt"), 4, 32)
+	const expected = "5bbf0cc293587f1c3635555c27796598d47e579071bf427e9d8fbe842aba34d9"
+	if err != nil || hex.EncodeToString(key) != expected {
+		t.Errorf("bcryptPBKDF() == %x, %v want %s", key, err, expected)
+	}
+	if _, err = bcryptPBKDF([]byte("password"), []byte("salt"), 0, 32); err == nil {
+		t.Errorf("bcryptPBKDF() with 0 rounds should fail")
+	}
+}
+
+func TestDeriveSSHKey(t *testing.T) {
+	seed := []byte{1, 2, 3, 4}
+	privateKey, err := DeriveSSHKey(&seed, "work", SSHKeyDerivationVersion)
+	if err != nil {
+		t.Fatalf("DeriveSSHKey() - %s", err)
+	}
+	publicKey, fingerprint, err := MarshalSSHPublicKey(privateKey.Public().(ed25519.PublicKey), "work key")
+	const expected = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAILxvl6nLESWd2DU6VbG7xRyyptxaR9c5doNZZtsCfam/ work key\n"
+	if err != nil || string(publicKey) != expected || fingerprint != "SHA256:3aNYEkTiFcU4nomINp56yz5jv4IqUV9223HhVzwtiEM" {
+		t.Errorf("MarshalSSHPublicKey() == %q, %s, %v want %q", publicKey, fingerprint, err, expected)
+	}
+	other, _ := DeriveSSHKey(&seed, "home", SSHKeyDerivationVersion)
+	if bytes.Equal(privateKey, other) {
+		t.Errorf("DeriveSSHKey() is the same for different labels")
+	}
+	digest := MakePasswordDigest(&seed, "work", "", 4)
+	if bytes.Equal(privateKey.Seed(), (*digest)[:]) {
+		t.Errorf("DeriveSSHKey() is the password digest of the label")
+	}
+	if _, err = DeriveSSHKey(&seed, "work", 2); err == nil {
+		t.Errorf("DeriveSSHKey() with an unknown version should fail")
+	}
+}
+
+// readSSHString returns the first string of the SSH wire format in the data and the remaining data
+func readSSHString(data []byte) (s []byte, remaining []byte) {
+	length := binary.BigEndian.Uint32(data)
+	return data[4 : 4+length], data[4+length:]
+}
+
+func TestMarshalSSHPrivateKey(t *testing.T) {
+	seed := []byte{1, 2, 3, 4}
+	privateKey, _ := DeriveSSHKey(&seed, "work", SSHKeyDerivationVersion)
+	pemBytes, err := MarshalSSHPrivateKey(privateKey, "work key", nil, io.ReadFull)
+	if err != nil {
+		t.Fatalf("MarshalSSHPrivateKey() - %s", err)
+	}
+	parsed, err := ssh.ParseRawPrivateKey(pemBytes)
+	if err != nil {
+		t.Fatalf("ssh.ParseRawPrivateKey() - %s", err)
+	}
+	if parsedKey, ok := parsed.(*ed25519.PrivateKey); !ok || !bytes.Equal(*parsedKey, privateKey) {
+		t.Errorf("ssh.ParseRawPrivateKey() == %v want the derived key", parsed)
+	}
+
+	passphrase := []byte("correct horse")
+	pemBytes, err = MarshalSSHPrivateKey(privateKey, "work key", &passphrase, io.ReadFull)
+	if err != nil {
+		t.Fatalf("MarshalSSHPrivateKey() with a passphrase - %s", err)
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != "OPENSSH PRIVATE KEY" || !strings.HasPrefix(string(block.Bytes), "openssh-key-v1\x00") {
+		t.Fatalf("MarshalSSHPrivateKey() with a passphrase is not an OpenSSH private key")
+	}
+	cipherName, data := readSSHString(block.Bytes[len("openssh-key-v1\x00"):])
+	kdfName, data := readSSHString(data)
+	kdfOptions, data := readSSHString(data)
+	_, data = readSSHString(data[4:]) // public key
+	private, _ := readSSHString(data)
+	salt, rounds := readSSHString(kdfOptions)
+	if string(cipherName) != "aes256-ctr" || string(kdfName) != "bcrypt" || binary.BigEndian.Uint32(rounds) != 16 || len(private)%aes.BlockSize != 0 {
+		t.Fatalf("MarshalSSHPrivateKey() with a passphrase uses %s, %s and %d rounds", cipherName, kdfName, binary.BigEndian.Uint32(rounds))
+	}
+	key, _ := bcryptPBKDF(passphrase, salt, 16, 48)
+	aesBlock, _ := aes.NewCipher(key[:32])
+	cipher.NewCTR(aesBlock, key[32:]).XORKeyStream(private, private)
+	if !bytes.Equal(private[:4], private[4:8]) || !bytes.Contains(private, privateKey) || !bytes.Contains(private, []byte("work key")) {
+		t.Errorf("MarshalSSHPrivateKey() with a passphrase can't be decrypted")
+	}
+}
@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/techsek/derivatex/constants"
+)
+
+// The vault is the data directory containing the seed file and the database of the
+// default profile, each named profile having its own seed file and database in
+// the profiles subdirectory of the vault.
+
+var (
+	vaultDirectory string
+	vaultProfile   string
+)
+
+const (
+	profilesDirectory  = "profiles"
+	currentProfileFile = "profile"
+)
+
+var profileNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// SetVault sets the vault directory and the profile to use, which default to the
+// DERIVATEX_HOME environment variable and the profile selected with UseProfile.
+// With fallbackToDefault, the default profile is used if the selected profile doesn't exist anymore.
+func SetVault(directory string, profile string, fallbackToDefault bool) (fellBack bool, err error) {
+	if directory == "" {
+		directory = os.Getenv(constants.VaultEnvironmentVariable)
+	}
+	if directory == "" {
+		directory, err = defaultVaultDirectory()
+		if err != nil {
+			return false, err
+		}
+	}
+	err = os.MkdirAll(directory, 0700)
+	if err != nil {
+		return false, err
+	}
+	if profile == "" {
+		content, err := ioutil.ReadFile(filepath.Join(directory, currentProfileFile))
+		if err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+		profile = strings.TrimSpace(string(content))
+		if fallbackToDefault && profile != "" && (checkProfileName(profile) != nil || !profileExists(directory, profile)) {
+			profile, fellBack = "", true
+		}
+	}
+	if profile == "" {
+		profile = constants.DefaultProfileName
+	}
+	err = checkProfileName(profile)
+	if err != nil {
+		return false, err
+	}
+	if !profileExists(directory, profile) {
+		return false, errors.New("Profile '" + profile + "' does not exist, create it with 'derivatex profile create " + profile + "'")
+	}
+	vaultDirectory = directory
+	vaultProfile = profile
+	return fellBack, nil
+}
+
+// checkProfileName returns an error if the profile name could resolve outside of the profiles directory
+func checkProfileName(profile string) error {
+	if !profileNameRegex.MatchString(profile) {
+		return errors.New("Profile name '" + profile + "' must only contain lowercase letters, digits, '-' and '_'")
+	}
+	return nil
+}
+
+// defaultVaultDirectory returns the directory of the executable if it contains a seed file,
+// for portable installs, and the XDG data directory otherwise
+func defaultVaultDirectory() (directory string, err error) {
+	ex, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	_, err = os.Stat(filepath.Join(filepath.Dir(ex), constants.SeedFilename))
+	if err == nil {
+		return filepath.Dir(ex), nil
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "derivatex"), nil
+}
+
+func profileDirectory(directory string, profile string) string {
+	if profile == constants.DefaultProfileName {
+		return directory
+	}
+	return filepath.Join(directory, profilesDirectory, profile)
+}
+
+func profileExists(directory string, profile string) bool {
+	info, err := os.Stat(profileDirectory(directory, profile))
+	return err == nil && info.IsDir()
+}
+
+// vaultPath returns the path of the file in the directory of the current profile
+func vaultPath(filename string) (path string, err error) {
+	if vaultDirectory == "" {
+		_, err = SetVault("", "", false)
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(profileDirectory(vaultDirectory, vaultProfile), filename), nil
+}
+
+// CurrentProfile returns the name of the profile in use
+func CurrentProfile() string {
+	return vaultProfile
+}
+
+// ListProfiles returns the names of the profiles of the vault, the default profile first
+func ListProfiles() (profiles []string, err error) {
+	profiles = []string{constants.DefaultProfileName}
+	files, err := ioutil.ReadDir(filepath.Join(vaultDirectory, profilesDirectory))
+	if os.IsNotExist(err) {
+		return profiles, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, file := range files {
+		if file.IsDir() && profileNameRegex.MatchString(file.Name()) {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+	return append(profiles, names...), nil
+}
+
+// CreateProfile creates the directory of a new profile in the vault
+func CreateProfile(profile string) error {
+	err := checkProfileName(profile)
+	if err != nil {
+		return err
+	}
+	if profileExists(vaultDirectory, profile) {
+		return errors.New("Profile '" + profile + "' already exists")
+	}
+	return os.MkdirAll(profileDirectory(vaultDirectory, profile), 0700)
+}
+
+// UseProfile records the profile to use by default for the next commands
+func UseProfile(profile string) error {
+	err := checkProfileName(profile)
+	if err != nil {
+		return err
+	}
+	if !profileExists(vaultDirectory, profile) {
+		return errors.New("Profile '" + profile + "' does not exist, create it with 'derivatex profile create " + profile + "'")
+	}
+	content := []byte(profile + "\n")
+	err = writeFileAtomically(filepath.Join(vaultDirectory, currentProfileFile), &content, 0600)
+	if err != nil {
+		return err
+	}
+	vaultProfile = profile
+	return nil
+}
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetVault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "derivatex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { vaultDirectory, vaultProfile = "", "" }()
+	environmentDir := filepath.Join(dir, "environment")
+	os.Setenv("DERIVATEX_HOME", environmentDir)
+	defer os.Unsetenv("DERIVATEX_HOME")
+
+	_, err = SetVault("", "", false)
+	if err != nil {
+		t.Fatalf("SetVault() - %s", err)
+	}
+	path, err := seedFilePath()
+	if err != nil {
+		t.Fatalf("seedFilePath() - %s", err)
+	}
+	if path != filepath.Join(environmentDir, "seed.txt") || CurrentProfile() != "default" {
+		t.Errorf("SetVault() with DERIVATEX_HOME uses %s with profile %s", path, CurrentProfile())
+	}
+
+	vaultDir := filepath.Join(dir, "vault")
+	_, err = SetVault(vaultDir, "work", false)
+	equal, m := errorsEqual(err, errors.New("Profile 'work' does not exist, create it with 'derivatex profile create work'"))
+	if !equal {
+		t.Errorf("SetVault() with a missing profile - %s", m)
+	}
+	_, err = SetVault(vaultDir, "", false)
+	if err != nil {
+		t.Fatalf("SetVault() - %s", err)
+	}
+	for _, profile := range []string{"work", "personal"} {
+		err = CreateProfile(profile)
+		if err != nil {
+			t.Fatalf("CreateProfile(%s) - %s", profile, err)
+		}
+	}
+	err = CreateProfile("work")
+	equal, m = errorsEqual(err, errors.New("Profile 'work' already exists"))
+	if !equal {
+		t.Errorf("CreateProfile() of an existing profile - %s", m)
+	}
+	err = CreateProfile("../work")
+	equal, m = errorsEqual(err, errors.New("Profile name '../work' must only contain lowercase letters, digits, '-' and '_'"))
+	if !equal {
+		t.Errorf("CreateProfile() with an invalid name - %s", m)
+	}
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() - %s", err)
+	}
+	if !reflect.DeepEqual(profiles, []string{"default", "personal", "work"}) {
+		t.Errorf("ListProfiles() == %v", profiles)
+	}
+
+	err = UseProfile("work")
+	if err != nil {
+		t.Fatalf("UseProfile() - %s", err)
+	}
+	_, err = SetVault(vaultDir, "", false)
+	if err != nil {
+		t.Fatalf("SetVault() - %s", err)
+	}
+	path, err = vaultPath("database.sqlite")
+	if err != nil {
+		t.Fatalf("vaultPath() - %s", err)
+	}
+	if path != filepath.Join(vaultDir, "profiles", "work", "database.sqlite") || CurrentProfile() != "work" {
+		t.Errorf("SetVault() after UseProfile() uses %s with profile %s", path, CurrentProfile())
+	}
+	_, err = SetVault(vaultDir, "personal", false)
+	if err != nil {
+		t.Fatalf("SetVault() - %s", err)
+	}
+	if CurrentProfile() != "personal" {
+		t.Errorf("SetVault() with a profile uses profile %s", CurrentProfile())
+	}
+	// the selected profile was deleted
+	err = os.RemoveAll(filepath.Join(vaultDir, "profiles", "work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = SetVault(vaultDir, "", false); err == nil {
+		t.Errorf("SetVault() with the selected profile deleted should fail without falling back")
+	}
+	fellBack, err := SetVault(vaultDir, "", true)
+	if err != nil || !fellBack || CurrentProfile() != "default" {
+		t.Errorf("SetVault() falling back with the selected profile deleted uses profile %s, %t - %v", CurrentProfile(), fellBack, err)
+	}
+	if _, err = SetVault(vaultDir, "work", true); err == nil {
+		t.Errorf("SetVault() with the deleted profile should fail")
+	}
+	// profile names resolving outside of the profiles directory
+	for _, profile := range []string{"../personal", "../../somewhere", "/tmp"} {
+		if _, err = SetVault(vaultDir, profile, true); err == nil {
+			t.Errorf("SetVault() with the profile %s should fail", profile)
+		}
+		if err = UseProfile(profile); err == nil {
+			t.Errorf("UseProfile() with the profile %s should fail", profile)
+		}
+	}
+	err = ioutil.WriteFile(filepath.Join(vaultDir, "profile"), []byte("../profiles/personal\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = SetVault(vaultDir, "", false); err == nil {
+		t.Errorf("SetVault() with a tampered selected profile should fail")
+	}
+}